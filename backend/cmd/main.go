@@ -10,12 +10,14 @@ import (
 	"os"
 	"strings"
 	"time"
+	_ "time/tzdata" // IANA zones for ?tz= on hosts without zoneinfo
 
 	"github-api/backend/internal/auth"
 	"github-api/backend/internal/cache"
 	"github-api/backend/internal/config"
 	"github-api/backend/internal/database"
 	"github-api/backend/internal/handlers"
+	"github-api/backend/internal/models"
 	"github-api/backend/internal/repository"
 	"github-api/backend/internal/service"
 
@@ -72,18 +74,30 @@ func main() {
 	log.Println("✅ Database schema initialized successfully")
 
 	// Initialize cache
-	cacheInstance := cache.New(cfg.MaxCacheSize, cfg.CacheTTL)
+	cacheInstance := cache.New[string, models.GitHubUser](cfg.MaxCacheSize, cfg.CacheTTL)
+	cacheInstance.SetStaleTTL(cfg.CacheStaleTTL)
 
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
 	rankingRepo := repository.NewRankingRepository(db)
 	privateDataRepo := repository.NewPrivateDataRepository(db)
 	devaiRepo := repository.NewDevAIRepository(db)
+	cacheRepo := repository.NewCacheRepository(db)
 
 	// Initialize services
 	githubService := service.NewGitHubService(cfg, cacheInstance)
+	githubService.SetCacheStore(cacheRepo)
+
+	// Warm the in-memory cache from the persistent cache so restarts don't start cold
+	warmCtx, warmCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if warmed, err := githubService.WarmCache(warmCtx); err != nil {
+		log.Printf("⚠️  Failed to warm cache from database: %v", err)
+	} else {
+		log.Printf("✅ Warmed cache with %d entries from database", warmed)
+	}
+	warmCancel()
 	rankingService := service.NewRankingService(rankingRepo, githubService)
-	privateDataService := service.NewPrivateDataService(privateDataRepo)
+	privateDataService := service.NewPrivateDataService(privateDataRepo, githubService.GitHub())
 
 	// Initialize auth service
 	authConfig := auth.GitHubOAuthConfig{
@@ -92,7 +106,7 @@ func main() {
 		RedirectURL:  cfg.GitHubRedirectURL,
 		Scopes:       []string{"read:user", "user:email", "repo"},
 	}
-	authService := auth.NewAuthService(authConfig, userRepo)
+	authService := auth.NewAuthService(authConfig, userRepo, githubService.GitHub())
 
 	// Initialize handlers
 	searchHandler := handlers.NewSearchHandler(userRepo)
@@ -102,7 +116,7 @@ func main() {
 	rankingHandler := handlers.NewRankingHandler(rankingService)
 	privateDataHandler := handlers.NewPrivateDataHandler(privateDataService, authService)
 	authMiddleware := handlers.NewAuthMiddleware(authService)
-	adminHandler := handlers.NewAdminHandler(db.DB)
+	adminHandler := handlers.NewAdminHandler(db.DB, githubService.GitHub())
 
 	// Setup routes - Public endpoints
 	http.HandleFunc("/", handlers.SecureCORSMiddleware(server.HomeHandler))
@@ -118,6 +132,7 @@ func main() {
 	http.HandleFunc("/api/auth/callback", handlers.SecurityMiddleware(authHandler.CallbackHandler)) // No CORS for OAuth callback
 	http.HandleFunc("/api/auth/logout", handlers.SecureCORSMiddleware(authHandler.LogoutHandler))
 	http.HandleFunc("/api/auth/me", handlers.SecureCORSMiddleware(authMiddleware.RequireAuth(authHandler.MeHandler)))
+	http.HandleFunc("/api/auth/me/preferences", handlers.SecureCORSMiddleware(authMiddleware.RequireAuth(authHandler.PreferencesHandler)))
 	http.HandleFunc("/api/auth/me/full", handlers.SecureCORSMiddleware(authMiddleware.RequireAuth(authHandler.MeFullHandler)))
 
 	// Search history endpoints (protected)
@@ -145,7 +160,13 @@ func main() {
 
 	// Cache endpoints (public for now, can be protected later)
 	http.HandleFunc("/api/cache/stats", handlers.SecureCORSMiddleware(server.CacheStatsHandler))
-	http.HandleFunc("/api/cache/clear", handlers.SecureCORSMiddleware(server.CacheClearHandler))
+	http.HandleFunc("/api/cache/clear", handlers.SecureCORSMiddleware(authMiddleware.RequireAuth(server.CacheClearHandler)))
+
+	// Cache administration (admin only)
+	http.HandleFunc("/api/admin/cache/keys", handlers.SecureCORSMiddleware(authMiddleware.RequireAuth(server.AdminCacheKeysHandler)))
+	http.HandleFunc("/api/admin/cache/entry", handlers.SecureCORSMiddleware(authMiddleware.RequireAuth(server.AdminCacheEntryHandler)))
+	http.HandleFunc("/api/admin/cache/invalidate", handlers.SecureCORSMiddleware(authMiddleware.RequireAuth(server.AdminCacheInvalidateHandler)))
+	http.HandleFunc("/api/admin/cache/config", handlers.SecureCORSMiddleware(authMiddleware.RequireAuth(server.AdminCacheConfigHandler)))
 
 	// User lookup endpoints (optionally authenticated)
 	http.HandleFunc("/api/status/", handlers.SecureCORSMiddleware(authMiddleware.OptionalAuth(server.GetStatusByPathHandler)))
@@ -163,6 +184,7 @@ func main() {
 			http.NotFound(w, r)
 		}
 	})))
+	http.HandleFunc("/api/compare", handlers.SecureCORSMiddleware(authMiddleware.OptionalAuth(server.CompareHandler)))
 	http.HandleFunc("/api/ai/compare", handlers.SecureCORSMiddleware(authMiddleware.OptionalAuth(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == "POST" {
 			server.AIComparisonHandler(w, r)
@@ -177,7 +199,10 @@ func main() {
 			http.NotFound(w, r)
 		}
 	})))
-	http.HandleFunc("/api/user/", handlers.SecureCORSMiddleware(authMiddleware.OptionalAuth(server.GetExtendedUserHandler)))
+	http.HandleFunc("/api/user/", handlers.SecureCORSMiddleware(authMiddleware.OptionalAuth(server.UsersHandler)))
+
+	// Repository analytics and health (public; fetched with the shared tokens only)
+	http.HandleFunc("/api/repos/", handlers.SecureCORSMiddleware(server.ReposHandler))
 
 	// Dev AI endpoints (authenticated)
 	http.HandleFunc("/api/devai/chat", handlers.SecureCORSMiddleware(authMiddleware.RequireAuth(func(w http.ResponseWriter, r *http.Request) {
@@ -218,7 +243,7 @@ func main() {
 	fmt.Println("=" + strings.Repeat("=", 75))
 	fmt.Printf("✅ Server: http://localhost%s\n", cfg.ServerPort)
 	fmt.Printf("🗄️  Database: PostgreSQL (Neon) Connected\n")
-	fmt.Printf("💾 Cache: Enabled (TTL: %v, Max: %d)\n", cfg.CacheTTL, cfg.MaxCacheSize)
+	fmt.Printf("💾 Cache: Enabled (TTL: %v, Stale: %v, Max: %d, L2: PostgreSQL)\n", cfg.CacheTTL, cfg.CacheStaleTTL, cfg.MaxCacheSize)
 	fmt.Printf("🔐 Auth: GitHub OAuth Enabled\n")
 	fmt.Printf("📊 Rankings: Enabled with scoring system\n")
 	fmt.Printf("📦 Architecture: Clean MVC with repository pattern\n")
@@ -233,7 +258,8 @@ func main() {
 	fmt.Println("   Auth:     GET  /api/auth/login (full access), /api/auth/login/basic")
 	fmt.Println("             POST /api/auth/logout, GET /api/auth/me")
 	fmt.Println("   Rankings: GET  /api/rankings, /api/rankings/{username}")
-	fmt.Println("   Users:    GET  /api/user/{username}, POST /api/batch")
+	fmt.Println("   Users:    GET  /api/user/{username}, /api/user/{username}/activity, /api/user/{username}/network, POST /api/batch, POST /api/compare")
+	fmt.Println("   Repos:    GET  /api/repos/{owner}/{repo}, /api/repos/{owner}/{repo}/health, /api/repos/{owner}/{repo}/contributors, /api/repos/{owner}/{repo}/velocity")
 	fmt.Println("   Search:   GET  /api/search/history (authenticated)")
 	fmt.Println("   AI:       POST /api/ai/compare")
 	fmt.Println("   Cache:    GET  /api/cache/stats, POST /api/cache/clear")
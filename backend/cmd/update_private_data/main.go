@@ -3,16 +3,17 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"os"
 	"strings"
 	"sync"
 	"time"
 
+	"github-api/backend/internal/github"
+
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 )
@@ -20,7 +21,7 @@ import (
 const (
 	defaultInterval = 60 * time.Minute
 	defaultWorkers  = 5
-	githubAPIBase   = "https://api.github.com"
+	githubTimeout   = 30 * time.Second
 
 	// Upsert query matching the existing schema in database/postgres.go
 	upsertQuery = `
@@ -142,6 +143,9 @@ func main() {
 		}
 	}
 
+	// Shared GitHub client; each request carries the user's own token
+	githubClient = github.NewClient(os.Getenv("GITHUB_API_URL"), "", githubTimeout)
+
 	// Get DATABASE_URL
 	dbURL := os.Getenv("DATABASE_URL")
 	if dbURL == "" {
@@ -259,7 +263,7 @@ func updateAllUsers(ctx context.Context, db *pgxpool.Pool, workers int) error {
 				failCount++
 				mu.Unlock()
 				// If the failure is due to bad credentials (401), disable private access for the user
-				if strings.Contains(err.Error(), "API error 401") || strings.Contains(strings.ToLower(err.Error()), "bad credentials") {
+				if errors.Is(err, github.ErrUnauthorized) {
 					if _, execErr := db.Exec(ctx, "UPDATE users SET has_private_access = FALSE WHERE id = $1", u.ID); execErr != nil {
 						log.Printf("[FAIL] Could not disable has_private_access for user %d (%s): %v", u.ID, u.Username, execErr)
 					} else {
@@ -445,48 +449,9 @@ func processUser(ctx context.Context, db *pgxpool.Pool, user UserRow) error {
 	return nil
 }
 
-func fetchGitHubAPI[T any](ctx context.Context, path, token string) (*T, error) {
-	url := githubAPIBase + path
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("User-Agent", "DevScope-PrivateDataUpdater")
-	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusUnauthorized {
-		return nil, fmt.Errorf("unauthorized: invalid or expired token")
-	}
-
-	if resp.StatusCode == http.StatusForbidden {
-		reset := resp.Header.Get("X-RateLimit-Reset")
-		return nil, fmt.Errorf("rate limited (reset: %s)", reset)
-	}
+// githubClient is initialised in main once the environment is loaded
+var githubClient *github.Client
 
-	if resp.StatusCode >= 400 {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	var result T
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("unmarshal: %w", err)
-	}
-
-	return &result, nil
+func fetchGitHubAPI[T any](ctx context.Context, path, token string) (*T, error) {
+	return github.GetJSON[T](ctx, githubClient.WithToken(token), path)
 }
@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"github-api/backend/internal/github"
 	"github-api/backend/internal/models"
 	"github-api/backend/internal/repository"
 )
@@ -29,16 +30,27 @@ type GitHubOAuthConfig struct {
 type AuthService struct {
 	config   GitHubOAuthConfig
 	userRepo *repository.UserRepository
+	github   *github.Client
 }
 
 // NewAuthService creates a new auth service
-func NewAuthService(config GitHubOAuthConfig, userRepo *repository.UserRepository) *AuthService {
+func NewAuthService(config GitHubOAuthConfig, userRepo *repository.UserRepository, client *github.Client) *AuthService {
 	return &AuthService{
 		config:   config,
 		userRepo: userRepo,
+		github:   client,
 	}
 }
 
+// GitHub returns a GitHub API client authenticated with a user's access token
+func (s *AuthService) GitHub(accessToken string) *github.Client {
+	client := s.github
+	if client == nil {
+		client = github.NewClient(github.DefaultBaseURL, "", 10*time.Second)
+	}
+	return client.WithToken(accessToken)
+}
+
 // GenerateStateToken generates a random state token for OAuth
 func GenerateStateToken() (string, error) {
 	b := make([]byte, 32)
@@ -189,49 +201,17 @@ type GitHubUserResponse struct {
 
 // GetGitHubUser fetches user information from GitHub API
 func (s *AuthService) GetGitHubUser(ctx context.Context, accessToken string) (*GitHubUserResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/user", nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+accessToken)
-	req.Header.Set("Accept", "application/json")
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	user, err := github.GetJSON[GitHubUserResponse](ctx, s.GitHub(accessToken), "/user")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch user: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GitHub API returned status: %d", resp.StatusCode)
-	}
-
-	var user GitHubUserResponse
-	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
-		return nil, fmt.Errorf("failed to decode user: %w", err)
-	}
-
-	return &user, nil
+	return user, nil
 }
 
 // CheckPrivateRepoAccess checks if token has private repo access
 func (s *AuthService) CheckPrivateRepoAccess(ctx context.Context, accessToken string) bool {
-	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/user/repos?type=private&per_page=1", nil)
-	if err != nil {
-		return false
-	}
-
-	req.Header.Set("Authorization", "Bearer "+accessToken)
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return false
-	}
-	defer resp.Body.Close()
-
-	return resp.StatusCode == http.StatusOK
+	_, err := s.GitHub(accessToken).Get(ctx, "/user/repos?type=private&per_page=1", nil)
+	return err == nil
 }
 
 // CreateOrUpdateUser creates or updates user in database
@@ -354,8 +334,9 @@ func (s *AuthService) CreateSession(ctx context.Context, userID int) (*models.Se
 	return session, nil
 }
 
-// ValidateSession validates a session token
-func (s *AuthService) ValidateSession(ctx context.Context, sessionID string) (*models.User, error) {
+// ValidateSession validates a session token and returns its user together
+// with their GitHub access token
+func (s *AuthService) ValidateSession(ctx context.Context, sessionID string) (*models.UserWithToken, error) {
 	session, err := s.userRepo.GetSession(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get session: %w", err)
@@ -365,7 +346,7 @@ func (s *AuthService) ValidateSession(ctx context.Context, sessionID string) (*m
 		return nil, fmt.Errorf("invalid or expired session")
 	}
 
-	user, err := s.userRepo.GetUserByID(ctx, session.UserID)
+	user, err := s.userRepo.GetUserWithTokenByID(ctx, session.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -384,31 +365,7 @@ func (s *AuthService) DeleteSession(ctx context.Context, sessionID string) error
 
 // GetFullGitHubUserData fetches the authenticated user's full GitHub data including private repos
 func (s *AuthService) GetFullGitHubUserData(ctx context.Context, accessToken string) (*GitHubUserResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/user", nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+accessToken)
-	req.Header.Set("Accept", "application/json")
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch user: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GitHub API returned status: %d", resp.StatusCode)
-	}
-
-	var user GitHubUserResponse
-	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
-		return nil, fmt.Errorf("failed to decode user: %w", err)
-	}
-
-	return &user, nil
+	return s.GetGitHubUser(ctx, accessToken)
 }
 
 // GetUserWithToken retrieves user with access token from database
@@ -3,8 +3,13 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
+
+	"github-api/backend/internal/github"
 )
 
 func TestGenerateStateToken(t *testing.T) {
@@ -95,3 +100,40 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func newTestAuthService(t *testing.T, handler http.HandlerFunc) *AuthService {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewAuthService(GitHubOAuthConfig{}, nil, github.NewClient(server.URL, "", 5*time.Second))
+}
+
+func TestGetGitHubUser(t *testing.T) {
+	svc := newTestAuthService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user" || r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Unexpected request %s (auth=%q)", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`{"id":1,"login":"octocat"}`))
+	})
+
+	user, err := svc.GetGitHubUser(context.Background(), "token")
+	if err != nil {
+		t.Fatalf("GetGitHubUser failed: %v", err)
+	}
+	if user.ID != 1 || user.Login != "octocat" {
+		t.Errorf("Unexpected user %+v", user)
+	}
+}
+
+func TestGetGitHubUserBadCredentials(t *testing.T) {
+	svc := newTestAuthService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	if _, err := svc.GetGitHubUser(context.Background(), "expired"); !errors.Is(err, github.ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+	if svc.CheckPrivateRepoAccess(context.Background(), "expired") {
+		t.Error("Private access should be denied for a rejected token")
+	}
+}
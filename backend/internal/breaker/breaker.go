@@ -0,0 +1,243 @@
+// Package breaker stops calling an upstream API while it is failing
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github-api/backend/internal/models"
+)
+
+// ErrOpen is matched with errors.Is by calls rejected while a breaker is open
+var ErrOpen = errors.New("breaker: upstream unavailable")
+
+// OpenError rejects a call without sending it
+type OpenError struct {
+	Upstream string
+	RetryAt  time.Time // when the breaker lets a trial call through
+}
+
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("%s is unavailable (retry after %s)", e.Upstream, e.RetryAt.Format(time.RFC3339))
+}
+
+// Is matches ErrOpen
+func (e *OpenError) Is(target error) bool {
+	return target == ErrOpen
+}
+
+// RetryAt returns when a rejected call may be tried again, or the zero time
+// if err was not caused by an open breaker
+func RetryAt(err error) time.Time {
+	var openErr *OpenError
+	if errors.As(err, &openErr) {
+		return openErr.RetryAt
+	}
+	return time.Time{}
+}
+
+// State is the position of a breaker
+type State int
+
+const (
+	Closed   State = iota // calls flow normally
+	Open                  // calls fail fast until the cooldown ends
+	HalfOpen              // a single trial call decides whether to close
+)
+
+func (s State) String() string {
+	switch s {
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return "closed"
+}
+
+// Breaker opens after Threshold consecutive failures and rejects calls for
+// Cooldown. It then lets one trial call through: success closes it again,
+// failure restarts the cooldown. A Breaker is safe for concurrent use.
+type Breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+	trips    int64
+	rejected int64
+}
+
+// New creates a closed breaker for the upstream called name
+func New(name string, threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		name:      name,
+		threshold: max(threshold, 1),
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a call may go ahead, returning an *OpenError if not.
+// Every allowed call must be followed by Success, Failure or Ignore.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == Open && b.now().Sub(b.openedAt) >= b.cooldown {
+		b.state = HalfOpen
+		b.probing = false
+	}
+	switch {
+	case b.state == Open,
+		b.state == HalfOpen && b.probing:
+		b.rejected++
+		return &OpenError{Upstream: b.name, RetryAt: b.openedAt.Add(b.cooldown)}
+	case b.state == HalfOpen:
+		b.probing = true
+	}
+	return nil
+}
+
+// Success records a call that reached a healthy upstream
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != Closed {
+		log.Printf("✅ [Breaker] %s recovered, closing circuit", b.name)
+	}
+	b.state = Closed
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure records a call the upstream failed
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == HalfOpen || b.state == Closed && b.failures >= b.threshold {
+		b.state = Open
+		b.openedAt = b.now()
+		b.probing = false
+		b.trips++
+		log.Printf("🔌 [Breaker] %s failing (%d consecutive failures), opening circuit for %v", b.name, b.failures, b.cooldown)
+	}
+}
+
+// Ignore releases an allowed call that ended without a verdict on the
+// upstream, such as one cancelled by its caller
+func (b *Breaker) Ignore() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// State returns the breaker's current position
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == Open && b.now().Sub(b.openedAt) >= b.cooldown {
+		return HalfOpen
+	}
+	return b.state
+}
+
+// Status reports the breaker for the health endpoint
+func (b *Breaker) Status() models.BreakerStatus {
+	state := b.State()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := models.BreakerStatus{
+		Upstream: b.name,
+		State:    state.String(),
+		Failures: b.failures,
+		Trips:    b.trips,
+		Rejected: b.rejected,
+	}
+	if state == Open {
+		retryAt := b.openedAt.Add(b.cooldown)
+		status.RetryAt = &retryAt
+	}
+	return status
+}
+
+// Transport is an http.RoundTripper guarded by a Breaker. Connection errors,
+// timeouts and 5xx responses count as failures; any other response counts
+// as success. A call abandoned by its caller, whether cancelled or past the
+// caller's own deadline, says nothing about the upstream and is ignored.
+type Transport struct {
+	breaker *Breaker
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+// NewTransport wraps base (http.DefaultTransport when nil) with b. A call
+// the upstream hasn't answered, body included, within timeout is cancelled
+// and counts as a failure; zero leaves calls bounded by their caller only.
+func NewTransport(b *Breaker, base http.RoundTripper, timeout time.Duration) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{breaker: b, base: base, timeout: timeout}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.breaker.Allow(); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	caller := req.Context()
+	cancel := context.CancelFunc(func() {})
+	if t.timeout > 0 {
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(caller, t.timeout)
+		req = req.WithContext(ctx)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	switch {
+	case err != nil && caller.Err() != nil:
+		t.breaker.Ignore()
+	case err != nil, resp.StatusCode >= 500:
+		t.breaker.Failure()
+	default:
+		t.breaker.Success()
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody releases a call's timeout once its body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
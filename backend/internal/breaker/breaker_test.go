@@ -0,0 +1,190 @@
+// Package breaker provides tests for the circuit breaker
+package breaker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestBreaker returns a breaker driven by a clock the test advances
+func newTestBreaker(threshold int, cooldown time.Duration) (*Breaker, *time.Time) {
+	now := time.Now()
+	b := New("test", threshold, cooldown)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	b, _ := newTestBreaker(3, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if err := b.Allow(); err != nil {
+			t.Fatalf("Breaker should stay closed below the threshold: %v", err)
+		}
+		b.Failure()
+	}
+	b.Allow()
+	b.Success()
+	if b.failures != 0 {
+		t.Fatal("A success should reset the failure count")
+	}
+
+	for i := 0; i < 3; i++ {
+		b.Allow()
+		b.Failure()
+	}
+	if b.State() != Open {
+		t.Fatalf("Expected open after 3 consecutive failures, got %v", b.State())
+	}
+
+	err := b.Allow()
+	if !errors.Is(err, ErrOpen) {
+		t.Fatalf("Expected ErrOpen, got %v", err)
+	}
+	if RetryAt(err).IsZero() {
+		t.Error("Rejection should say when to retry")
+	}
+	if status := b.Status(); status.State != "open" || status.Trips != 1 || status.Rejected != 1 || status.RetryAt == nil {
+		t.Errorf("Unexpected status %+v", status)
+	}
+}
+
+func TestBreakerHalfOpenAllowsSingleTrial(t *testing.T) {
+	b, now := newTestBreaker(1, time.Minute)
+	b.Allow()
+	b.Failure()
+
+	*now = now.Add(time.Minute)
+	if b.State() != HalfOpen {
+		t.Fatalf("Expected half-open after the cooldown, got %v", b.State())
+	}
+	if err := b.Allow(); err != nil {
+		t.Fatalf("The trial call should be allowed: %v", err)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatal("Only one trial call should be in flight")
+	}
+
+	b.Success()
+	if b.State() != Closed {
+		t.Errorf("A successful trial should close the breaker, got %v", b.State())
+	}
+}
+
+func TestBreakerFailedTrialReopens(t *testing.T) {
+	b, now := newTestBreaker(1, time.Minute)
+	b.Allow()
+	b.Failure()
+
+	*now = now.Add(time.Minute)
+	b.Allow()
+	b.Failure()
+
+	if b.State() != Open {
+		t.Fatalf("A failed trial should reopen the breaker, got %v", b.State())
+	}
+	if got := RetryAt(b.Allow()); !got.Equal(now.Add(time.Minute)) {
+		t.Errorf("Cooldown should restart from the failed trial, retry at %v", got)
+	}
+}
+
+func TestBreakerIgnoreReleasesTrial(t *testing.T) {
+	b, now := newTestBreaker(1, time.Minute)
+	b.Allow()
+	b.Failure()
+
+	*now = now.Add(time.Minute)
+	b.Allow()
+	b.Ignore()
+	if err := b.Allow(); err != nil {
+		t.Errorf("A cancelled trial should let the next call try: %v", err)
+	}
+}
+
+func TestTransportFailsFastWhileOpen(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(server.Close)
+
+	b := New("test", 2, time.Minute)
+	client := &http.Client{Transport: NewTransport(b, nil, 0)}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, ErrOpen) {
+		t.Fatalf("Expected the third call to fail fast, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("An open breaker should not reach the upstream, got %d calls", calls.Load())
+	}
+}
+
+func TestTransportIgnoresCancelledCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	b := New("test", 1, time.Minute)
+	client := &http.Client{Transport: NewTransport(b, nil, 0)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	time.AfterFunc(20*time.Millisecond, cancel)
+	client.Do(req)
+
+	if b.State() != Closed {
+		t.Errorf("A caller giving up is not an upstream failure, got %v", b.State())
+	}
+}
+
+func TestTransportIgnoresCallerDeadlines(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	b := New("test", 1, time.Minute)
+	client := &http.Client{Transport: NewTransport(b, nil, time.Minute)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := client.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the caller's deadline to end the call, got %v", err)
+	}
+
+	if b.State() != Closed {
+		t.Errorf("A caller running out of time is not an upstream failure, got %v", b.State())
+	}
+}
+
+func TestTransportCountsTimeouts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	b := New("test", 1, time.Minute)
+	client := &http.Client{Transport: NewTransport(b, nil, 20*time.Millisecond)}
+
+	client.Get(server.URL)
+	if b.State() != Open {
+		t.Errorf("A timed out call should count as a failure, got %v", b.State())
+	}
+}
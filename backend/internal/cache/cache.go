@@ -2,6 +2,7 @@
 package cache
 
 import (
+	"container/list"
 	"fmt"
 	"sync"
 	"time"
@@ -9,134 +10,389 @@ import (
 	"github-api/backend/internal/models"
 )
 
-// Entry represents a cache entry with expiry
-type Entry struct {
-	Data      models.GitHubUser
-	ExpiresAt time.Time
+// Entry represents a cache entry with expiry.
+// An entry is fresh until ExpiresAt and may still be served as stale
+// until StaleUntil, after which it is dropped.
+type Entry[K comparable, V any] struct {
+	Key        K
+	Data       V
+	StoredAt   time.Time
+	ExpiresAt  time.Time
+	StaleUntil time.Time
+
+	// TTL the entry was stored with; Touch restarts it with the same TTL
+	TTL time.Duration
+
+	// HTTP validators of the upstream response, used to revalidate the
+	// entry with a conditional request once it expires
+	ETag         string
+	LastModified string
 }
 
-// Cache represents a thread-safe LRU cache with TTL
-type Cache struct {
-	data        map[string]Entry
-	accessOrder []string
-	mu          sync.RWMutex
-	maxSize     int
-	ttl         time.Duration
-	hits        int64
-	misses      int64
+// Info describes the entry's age and remaining lifetime at the given time
+func (e *Entry[K, V]) Info(now time.Time) models.CacheKeyInfo {
+	return models.CacheKeyInfo{
+		Key:                 fmt.Sprint(e.Key),
+		AgeSeconds:          now.Sub(e.StoredAt).Seconds(),
+		TTLRemainingSeconds: max(e.ExpiresAt.Sub(now).Seconds(), 0),
+		Stale:               now.After(e.ExpiresAt),
+		ETag:                e.ETag,
+		LastModified:        e.LastModified,
+	}
+}
+
+// Cache represents a thread-safe LRU cache with TTL.
+// Lookups, inserts and evictions are O(1): entries live in a map that points
+// into a doubly linked list ordered from most to least recently used.
+type Cache[K comparable, V any] struct {
+	data      map[K]*list.Element
+	order     *list.List
+	mu        sync.Mutex
+	maxSize   int
+	ttl       time.Duration
+	staleFor  time.Duration
+	hits      int64
+	staleHits int64
+	misses    int64
+
+	// Conditional requests made for expired entries, and how many of them
+	// the upstream answered with 304 Not Modified
+	revalidations int64
+	notModified   int64
+
+	// Entries dropped to make room, and entries dropped past their stale window
+	evictions   int64
+	expirations int64
 }
 
 // New creates a new cache instance
-func New(maxSize int, ttl time.Duration) *Cache {
-	return &Cache{
-		data:        make(map[string]Entry),
-		accessOrder: make([]string, 0),
-		maxSize:     maxSize,
-		ttl:         ttl,
+func New[K comparable, V any](maxSize int, ttl time.Duration) *Cache[K, V] {
+	return &Cache[K, V]{
+		data:    make(map[K]*list.Element),
+		order:   list.New(),
+		maxSize: maxSize,
+		ttl:     ttl,
+	}
+}
+
+// SetStaleTTL enables stale-while-revalidate: entries older than the cache
+// TTL but younger than hardTTL are still returned by Lookup, flagged stale.
+// A hardTTL at or below the cache TTL disables the stale window.
+func (c *Cache[K, V]) SetStaleTTL(hardTTL time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.staleFor = max(hardTTL-c.ttl, 0)
+}
+
+// TTL returns the default entry TTL
+func (c *Cache[K, V]) TTL() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ttl
+}
+
+// SetTTL changes the default TTL for entries stored from now on.
+// Existing entries keep their expiry; the stale window length is unchanged.
+func (c *Cache[K, V]) SetTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+}
+
+// MaxSize returns the maximum number of entries (0 means unbounded)
+func (c *Cache[K, V]) MaxSize() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.maxSize
+}
+
+// Resize changes the maximum number of entries, evicting least recently
+// used entries if the cache is over the new limit
+func (c *Cache[K, V]) Resize(maxSize int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxSize = maxSize
+	for c.maxSize > 0 && len(c.data) > c.maxSize {
+		c.removeElement(c.order.Back())
+		c.evictions++
 	}
 }
 
-// Get retrieves a value from cache
-func (c *Cache) Get(key string) (models.GitHubUser, bool) {
-	c.mu.RLock()
-	entry, exists := c.data[key]
-	c.mu.RUnlock()
+// MaxAge returns how long an entry may be served, including the stale window
+func (c *Cache[K, V]) MaxAge() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ttl + c.staleFor
+}
+
+// Get retrieves a fresh value from cache
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	value, _, found := c.lookup(key, false)
+	return value, found
+}
 
+// Lookup retrieves a value from cache, including entries past their TTL
+// that are still inside the stale window
+func (c *Cache[K, V]) Lookup(key K) (value V, stale bool, found bool) {
+	return c.lookup(key, true)
+}
+
+func (c *Cache[K, V]) lookup(key K, allowStale bool) (value V, stale bool, found bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, exists := c.data[key]
 	if !exists {
-		c.mu.Lock()
 		c.misses++
-		c.mu.Unlock()
-		return models.GitHubUser{}, false
+		return value, false, false
 	}
 
-	// Check if expired
-	if time.Now().After(entry.ExpiresAt) {
-		c.mu.Lock()
-		delete(c.data, key)
-		c.removeFromAccessOrder(key)
+	entry := elem.Value.(*Entry[K, V])
+	now := time.Now()
+
+	// Check if expired beyond the stale window
+	if now.After(entry.StaleUntil) {
+		c.removeElement(elem)
+		c.expirations++
 		c.misses++
-		c.mu.Unlock()
-		return models.GitHubUser{}, false
+		return value, false, false
 	}
 
-	// Update access order
-	c.mu.Lock()
-	c.updateAccessOrder(key)
+	if now.After(entry.ExpiresAt) {
+		if !allowStale {
+			// An entry with validators is revalidated rather than missed
+			if !entry.hasValidators() {
+				c.misses++
+			}
+			return value, false, false
+		}
+		c.order.MoveToFront(elem)
+		c.staleHits++
+		return entry.Data, true, true
+	}
+	c.order.MoveToFront(elem)
 	c.hits++
-	c.mu.Unlock()
 
+	return entry.Data, false, true
+}
+
+// Set adds a value to cache using the default TTL
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL adds a value to cache with a per-entry TTL
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.SetWithValidators(key, value, ttl, "", "")
+}
+
+// SetWithValidators adds a value to cache with a per-entry TTL and the
+// ETag and Last-Modified values of the response it came from
+func (c *Cache[K, V]) SetWithValidators(key K, value V, ttl time.Duration, etag, lastModified string) {
+	c.store(key, value, time.Now(), ttl, etag, lastModified)
+}
+
+// store adds a value that was fetched at storedAt and expires ttl later
+func (c *Cache[K, V]) store(key K, value V, storedAt time.Time, ttl time.Duration, etag, lastModified string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := storedAt.Add(ttl)
+	staleUntil := expiresAt.Add(c.staleFor)
+
+	if elem, exists := c.data[key]; exists {
+		entry := elem.Value.(*Entry[K, V])
+		entry.Data = value
+		entry.StoredAt = storedAt
+		entry.ExpiresAt = expiresAt
+		entry.StaleUntil = staleUntil
+		entry.TTL = ttl
+		entry.ETag = etag
+		entry.LastModified = lastModified
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	// Remove least recently used entry if at capacity
+	if c.maxSize > 0 && len(c.data) >= c.maxSize {
+		if oldest := c.order.Back(); oldest != nil {
+			c.removeElement(oldest)
+			c.evictions++
+		}
+	}
+
+	c.data[key] = c.order.PushFront(&Entry[K, V]{
+		Key:          key,
+		Data:         value,
+		StoredAt:     storedAt,
+		ExpiresAt:    expiresAt,
+		StaleUntil:   staleUntil,
+		TTL:          ttl,
+		ETag:         etag,
+		LastModified: lastModified,
+	})
+}
+
+// Revalidate returns the ETag and Last-Modified values stored with a live
+// entry (fresh or stale) for a conditional request, counting a revalidation
+func (c *Cache[K, V]) Revalidate(key K) (etag, lastModified string, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, exists := c.data[key]
+	if !exists {
+		return "", "", false
+	}
+	entry := elem.Value.(*Entry[K, V])
+	if time.Now().After(entry.StaleUntil) || !entry.hasValidators() {
+		return "", "", false
+	}
+	c.revalidations++
+	return entry.ETag, entry.LastModified, true
+}
+
+// Touch marks an entry as revalidated: its data is kept as is and the TTL it
+// was stored with starts over, as after a 304 Not Modified response. Entries
+// stored already expired get the default TTL.
+func (c *Cache[K, V]) Touch(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	elem, exists := c.data[key]
+	if !exists {
+		return zero, false
+	}
+	entry := elem.Value.(*Entry[K, V])
+	now := time.Now()
+	if now.After(entry.StaleUntil) {
+		return zero, false
+	}
+
+	if entry.TTL <= 0 {
+		entry.TTL = c.ttl
+	}
+	entry.StoredAt = now
+	entry.ExpiresAt = now.Add(entry.TTL)
+	entry.StaleUntil = entry.ExpiresAt.Add(c.staleFor)
+	c.order.MoveToFront(elem)
+	c.notModified++
 	return entry.Data, true
 }
 
-// Set adds a value to cache
-func (c *Cache) Set(key string, value models.GitHubUser) {
+// Peek returns a copy of an entry, including stale ones, without touching
+// LRU order or hit statistics
+func (c *Cache[K, V]) Peek(key K) (Entry[K, V], bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	// Remove oldest if at capacity
-	if len(c.data) >= c.maxSize {
-		if _, exists := c.data[key]; !exists {
-			if len(c.accessOrder) > 0 {
-				oldest := c.accessOrder[0]
-				delete(c.data, oldest)
-				c.accessOrder = c.accessOrder[1:]
-			}
+	elem, exists := c.data[key]
+	if !exists || time.Now().After(elem.Value.(*Entry[K, V]).StaleUntil) {
+		return Entry[K, V]{}, false
+	}
+	return *elem.Value.(*Entry[K, V]), true
+}
+
+// Keys describes every live entry from most to least recently used
+func (c *Cache[K, V]) Keys() []models.CacheKeyInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	keys := make([]models.CacheKeyInfo, 0, len(c.data))
+	for elem := c.order.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*Entry[K, V])
+		if now.After(entry.StaleUntil) {
+			continue
 		}
+		keys = append(keys, entry.Info(now))
 	}
+	return keys
+}
+
+// Delete removes a single entry from cache
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	c.data[key] = Entry{
-		Data:      value,
-		ExpiresAt: time.Now().Add(c.ttl),
+	elem, exists := c.data[key]
+	if !exists {
+		return false
 	}
-	c.updateAccessOrder(key)
+	c.removeElement(elem)
+	return true
+}
+
+// DeleteFunc removes every entry whose key matches, returning how many were removed
+func (c *Cache[K, V]) DeleteFunc(match func(K) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for elem := c.order.Front(); elem != nil; {
+		next := elem.Next()
+		if match(elem.Value.(*Entry[K, V]).Key) {
+			c.removeElement(elem)
+			removed++
+		}
+		elem = next
+	}
+	return removed
 }
 
 // Clear removes all cache entries
-func (c *Cache) Clear() {
+func (c *Cache[K, V]) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.data = make(map[string]Entry)
-	c.accessOrder = make([]string, 0)
+	c.data = make(map[K]*list.Element)
+	c.order.Init()
 	c.hits = 0
+	c.staleHits = 0
 	c.misses = 0
+	c.evictions = 0
+	c.expirations = 0
+	c.revalidations = 0
+	c.notModified = 0
 }
 
 // Size returns current cache size
-func (c *Cache) Size() int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+func (c *Cache[K, V]) Size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return len(c.data)
 }
 
 // Stats returns cache statistics
-func (c *Cache) Stats() models.CacheStats {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+func (c *Cache[K, V]) Stats() models.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	total := c.hits + c.misses
+	total := c.hits + c.staleHits + c.misses
 	hitRate := 0.0
 	if total > 0 {
-		hitRate = float64(c.hits) / float64(total) * 100
+		hitRate = float64(c.hits+c.staleHits) / float64(total) * 100
 	}
 
 	return models.CacheStats{
-		Size:    len(c.data),
-		Hits:    c.hits,
-		Misses:  c.misses,
-		HitRate: fmt.Sprintf("%.2f", hitRate),
+		Size:          len(c.data),
+		MaxSize:       c.maxSize,
+		TTLSeconds:    c.ttl.Seconds(),
+		Hits:          c.hits,
+		StaleHits:     c.staleHits,
+		Misses:        c.misses,
+		Revalidations: c.revalidations,
+		NotModified:   c.notModified,
+		Evictions:     c.evictions,
+		Expirations:   c.expirations,
+		HitRate:       fmt.Sprintf("%.2f", hitRate),
 	}
 }
 
-func (c *Cache) updateAccessOrder(key string) {
-	c.removeFromAccessOrder(key)
-	c.accessOrder = append(c.accessOrder, key)
+func (e *Entry[K, V]) hasValidators() bool {
+	return e.ETag != "" || e.LastModified != ""
 }
 
-func (c *Cache) removeFromAccessOrder(key string) {
-	for i, k := range c.accessOrder {
-		if k == key {
-			c.accessOrder = append(c.accessOrder[:i], c.accessOrder[i+1:]...)
-			break
-		}
-	}
+func (c *Cache[K, V]) removeElement(elem *list.Element) {
+	entry := c.order.Remove(elem).(*Entry[K, V])
+	delete(c.data, entry.Key)
 }
@@ -0,0 +1,264 @@
+// Package cache provides tests for the LRU cache
+package cache
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLRUEviction(t *testing.T) {
+	c := New[string, int](2, time.Minute)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	// Touch "a" so "b" becomes the least recently used entry
+	if _, found := c.Get("a"); !found {
+		t.Fatal("Expected a to be cached")
+	}
+
+	c.Set("c", 3)
+
+	if _, found := c.Get("b"); found {
+		t.Error("Expected b to be evicted")
+	}
+	if v, found := c.Get("a"); !found || v != 1 {
+		t.Errorf("Expected a=1, got %v (found=%v)", v, found)
+	}
+	if v, found := c.Get("c"); !found || v != 3 {
+		t.Errorf("Expected c=3, got %v (found=%v)", v, found)
+	}
+	if c.Size() != 2 {
+		t.Errorf("Expected size 2, got %d", c.Size())
+	}
+}
+
+func TestUpdateDoesNotEvict(t *testing.T) {
+	c := New[string, int](2, time.Minute)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("a", 10)
+
+	if c.Size() != 2 {
+		t.Errorf("Expected size 2, got %d", c.Size())
+	}
+	if v, _ := c.Get("a"); v != 10 {
+		t.Errorf("Expected updated value 10, got %d", v)
+	}
+	if _, found := c.Get("b"); !found {
+		t.Error("Updating an existing key should not evict other entries")
+	}
+}
+
+func TestPerEntryTTL(t *testing.T) {
+	c := New[string, string](10, time.Minute)
+
+	c.SetWithTTL("short", "x", -time.Second)
+	c.Set("long", "y")
+
+	if _, found := c.Get("short"); found {
+		t.Error("Expired entry should not be returned")
+	}
+	if _, found := c.Get("long"); !found {
+		t.Error("Entry within TTL should be returned")
+	}
+
+	stats := c.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss, got %d hits and %d misses", stats.Hits, stats.Misses)
+	}
+	if stats.Size != 1 {
+		t.Errorf("Expired entry should be removed, size=%d", stats.Size)
+	}
+}
+
+func TestDeleteAndClear(t *testing.T) {
+	c := New[int, []string](10, time.Minute)
+
+	c.Set(1, []string{"go"})
+	c.Set(2, []string{"rust"})
+
+	if !c.Delete(1) {
+		t.Error("Delete should report an existing key")
+	}
+	if c.Delete(1) {
+		t.Error("Delete should report a missing key")
+	}
+
+	c.Clear()
+	if c.Size() != 0 {
+		t.Errorf("Expected empty cache after Clear, got %d", c.Size())
+	}
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	c := New[string, string](10, time.Minute)
+	c.SetStaleTTL(time.Hour)
+
+	// Past the soft TTL but inside the stale window
+	c.SetWithTTL("user", "octocat", -time.Second)
+
+	if _, found := c.Get("user"); found {
+		t.Error("Get should not return stale entries")
+	}
+
+	v, stale, found := c.Lookup("user")
+	if !found || !stale || v != "octocat" {
+		t.Errorf("Expected stale hit for octocat, got %q (stale=%v, found=%v)", v, stale, found)
+	}
+
+	c.Set("user", "octocat")
+	if _, stale, found := c.Lookup("user"); !found || stale {
+		t.Errorf("Refreshed entry should be fresh (stale=%v, found=%v)", stale, found)
+	}
+
+	stats := c.Stats()
+	if stats.StaleHits != 1 {
+		t.Errorf("Expected 1 stale hit, got %d", stats.StaleHits)
+	}
+}
+
+func TestStaleWindowDisabled(t *testing.T) {
+	c := New[string, string](10, time.Minute)
+
+	c.SetWithTTL("user", "octocat", -time.Second)
+	if _, _, found := c.Lookup("user"); found {
+		t.Error("Without a stale TTL, expired entries should be dropped")
+	}
+}
+
+func TestEvictionAndExpirationCounters(t *testing.T) {
+	c := New[string, int](2, time.Minute)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.SetWithTTL("d", 4, -time.Second)
+	c.Get("d")
+
+	stats := c.Stats()
+	if stats.Evictions != 2 {
+		t.Errorf("Expected 2 evictions, got %d", stats.Evictions)
+	}
+	if stats.Expirations != 1 {
+		t.Errorf("Expected 1 expiration, got %d", stats.Expirations)
+	}
+}
+
+func TestKeysAndPeek(t *testing.T) {
+	c := New[string, string](10, time.Minute)
+	c.Set("old", "x")
+	c.Set("new", "y")
+
+	keys := c.Keys()
+	if len(keys) != 2 || keys[0].Key != "new" || keys[1].Key != "old" {
+		t.Fatalf("Expected keys ordered by recency, got %+v", keys)
+	}
+	if keys[0].TTLRemainingSeconds <= 0 || keys[0].TTLRemainingSeconds > 60 {
+		t.Errorf("Unexpected TTL remaining %.2f", keys[0].TTLRemainingSeconds)
+	}
+
+	entry, found := c.Peek("old")
+	if !found || entry.Data != "x" {
+		t.Errorf("Expected to peek old=x, got %+v (found=%v)", entry, found)
+	}
+	if stats := c.Stats(); stats.Hits != 0 || stats.Misses != 0 {
+		t.Error("Keys and Peek should not affect hit statistics")
+	}
+	if c.Keys()[0].Key != "new" {
+		t.Error("Peek should not change LRU order")
+	}
+}
+
+func TestDeleteFunc(t *testing.T) {
+	c := New[string, int](10, time.Minute)
+	c.Set("octocat", 1)
+	c.Set("octo-org", 2)
+	c.Set("torvalds", 3)
+
+	removed := c.DeleteFunc(func(key string) bool { return strings.HasPrefix(key, "octo") })
+	if removed != 2 || c.Size() != 1 {
+		t.Errorf("Expected 2 removed and 1 left, got removed=%d size=%d", removed, c.Size())
+	}
+}
+
+func TestSetTTLAndResize(t *testing.T) {
+	c := New[string, int](3, time.Minute)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+
+	c.Resize(1)
+	if c.Size() != 1 || c.MaxSize() != 1 {
+		t.Fatalf("Expected size 1 after resize, got size=%d max=%d", c.Size(), c.MaxSize())
+	}
+	if _, found := c.Get("c"); !found {
+		t.Error("Resize should keep the most recently used entry")
+	}
+
+	c.SetTTL(time.Hour)
+	c.Set("d", 4)
+	if ttl := c.Keys()[0].TTLRemainingSeconds; ttl <= 60 {
+		t.Errorf("New entries should use the updated TTL, got %.2fs", ttl)
+	}
+}
+
+func TestRevalidateAndTouch(t *testing.T) {
+	c := New[string, string](10, time.Minute)
+	c.SetStaleTTL(time.Hour)
+
+	c.SetWithValidators("user", "octocat", -time.Second, `"abc"`, "")
+	if _, found := c.Get("user"); found {
+		t.Fatal("Expired entry should not be returned by Get")
+	}
+
+	etag, _, ok := c.Revalidate("user")
+	if !ok || etag != `"abc"` {
+		t.Fatalf("Expected validators of the expired entry, got %q (ok=%v)", etag, ok)
+	}
+
+	v, ok := c.Touch("user")
+	if !ok || v != "octocat" {
+		t.Fatalf("Touch should return the cached value, got %q (ok=%v)", v, ok)
+	}
+	if v, found := c.Get("user"); !found || v != "octocat" {
+		t.Error("Touched entry should be fresh again")
+	}
+
+	stats := c.Stats()
+	if stats.Misses != 0 || stats.Revalidations != 1 || stats.NotModified != 1 {
+		t.Errorf("Expected 0 misses, 1 revalidation and 1 not modified, got %+v", stats)
+	}
+}
+
+func TestTouchKeepsEntryTTL(t *testing.T) {
+	c := New[string, string](10, time.Minute)
+	c.SetStaleTTL(time.Hour)
+
+	c.SetWithValidators("notifications", "[]", 10*time.Hour, `"abc"`, "")
+	if _, ok := c.Touch("notifications"); !ok {
+		t.Fatal("Touch should find the entry")
+	}
+
+	ttl := c.Keys()[0].TTLRemainingSeconds
+	if ttl <= time.Minute.Seconds() || ttl > (10*time.Hour).Seconds() {
+		t.Errorf("Touch should restart the entry's own 10h TTL, got %.2fs", ttl)
+	}
+}
+
+func TestRevalidateWithoutValidators(t *testing.T) {
+	c := New[string, string](10, time.Minute)
+	c.SetStaleTTL(time.Hour)
+
+	c.SetWithTTL("user", "octocat", -time.Second)
+	c.Get("user")
+
+	if _, _, ok := c.Revalidate("user"); ok {
+		t.Error("Entries stored without validators cannot be revalidated")
+	}
+	if c.Stats().Misses != 1 {
+		t.Errorf("Expected an expired entry without validators to count as a miss, got %d", c.Stats().Misses)
+	}
+}
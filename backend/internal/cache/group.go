@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// call represents an in-flight or completed Group.Do call
+type call[V any] struct {
+	done chan struct{}
+	val  V
+	err  error
+
+	// waiters counts callers still waiting on the call; cancel stops it
+	// once none are left (both guarded by Group.mu)
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// Group coalesces concurrent calls for the same key into a single execution.
+// Callers that arrive while a call is in flight wait for it and share its
+// result and error. The zero value is ready to use.
+type Group[K comparable, V any] struct {
+	mu        sync.Mutex
+	calls     map[K]*call[V]
+	coalesced atomic.Int64
+}
+
+// Do executes fn once per key for all concurrent callers
+func (g *Group[K, V]) Do(key K, fn func() (V, error)) (V, error) {
+	return g.DoContext(context.Background(), key, func(context.Context) (V, error) {
+		return fn()
+	})
+}
+
+// DoContext executes fn once per key for all concurrent callers. fn runs with
+// a context carrying the first caller's values that is cancelled only once
+// every caller has given up, so one caller going away does not fail the
+// others. A caller whose ctx ends stops waiting and gets ctx.Err().
+func (g *Group[K, V]) DoContext(ctx context.Context, key K, fn func(context.Context) (V, error)) (V, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+	}
+	c, ok := g.calls[key]
+	if ok {
+		g.coalesced.Add(1)
+	} else {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		c = &call[V]{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = c
+		go g.run(callCtx, key, c, fn)
+	}
+	c.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.val, c.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		c.waiters--
+		if c.waiters == 0 {
+			c.cancel()
+			g.forget(key, c)
+		}
+		g.mu.Unlock()
+
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// run executes fn for c and releases its waiters. fn runs on its own
+// goroutine, so a panic is returned to the waiters as an error rather than
+// taking the server down.
+func (g *Group[K, V]) run(ctx context.Context, key K, c *call[V], fn func(context.Context) (V, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.err = fmt.Errorf("cache: call for %v panicked: %v", key, r)
+		}
+		g.mu.Lock()
+		g.forget(key, c)
+		g.mu.Unlock()
+		c.cancel()
+		close(c.done)
+	}()
+	c.val, c.err = fn(ctx)
+}
+
+// forget removes c so that later callers start a new call. The caller must hold g.mu.
+func (g *Group[K, V]) forget(key K, c *call[V]) {
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+}
+
+// Coalesced returns how many calls were served by another caller's execution
+func (g *Group[K, V]) Coalesced() int64 {
+	return g.coalesced.Load()
+}
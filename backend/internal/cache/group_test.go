@@ -0,0 +1,174 @@
+// Package cache provides tests for request coalescing
+package cache
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroupCoalescesConcurrentCalls(t *testing.T) {
+	var g Group[string, int]
+	var executions atomic.Int32
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make([]int, callers)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _ = g.Do("octocat", func() (int, error) {
+			executions.Add(1)
+			close(started)
+			<-release
+			return 42, nil
+		})
+	}()
+	<-started
+
+	for i := 1; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = g.Do("octocat", func() (int, error) {
+				executions.Add(1)
+				return -1, nil
+			})
+		}(i)
+	}
+
+	// Wait until every follower is waiting on the in-flight call
+	for g.Coalesced() < callers-1 {
+		runtime.Gosched()
+	}
+	close(release)
+	wg.Wait()
+
+	if executions.Load() != 1 {
+		t.Errorf("Expected 1 execution, got %d", executions.Load())
+	}
+	for i, v := range results {
+		if v != 42 {
+			t.Errorf("Caller %d got %d, want 42", i, v)
+		}
+	}
+	if g.Coalesced() != callers-1 {
+		t.Errorf("Expected %d coalesced calls, got %d", callers-1, g.Coalesced())
+	}
+}
+
+func TestGroupSharesErrorsAndForgetsKeys(t *testing.T) {
+	var g Group[string, int]
+	wantErr := errors.New("upstream failed")
+
+	if _, err := g.Do("a", func() (int, error) { return 0, wantErr }); err != wantErr {
+		t.Errorf("Expected upstream error, got %v", err)
+	}
+
+	// A completed call must not be reused
+	v, err := g.Do("a", func() (int, error) { return 7, nil })
+	if err != nil || v != 7 {
+		t.Errorf("Expected fresh execution to return 7, got %d (%v)", v, err)
+	}
+}
+
+func TestGroupCancelsOnlyWhenEveryCallerLeaves(t *testing.T) {
+	var g Group[string, int]
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	release := make(chan struct{})
+
+	first, cancelFirst := context.WithCancel(context.Background())
+	second, cancelSecond := context.WithCancel(context.Background())
+	defer cancelSecond()
+
+	errs := make(chan error, 2)
+	go func() {
+		_, err := g.DoContext(first, "octocat", func(ctx context.Context) (int, error) {
+			close(started)
+			select {
+			case <-ctx.Done():
+				close(cancelled)
+				return 0, ctx.Err()
+			case <-release:
+				return 42, nil
+			}
+		})
+		errs <- err
+	}()
+	<-started
+
+	results := make(chan int, 1)
+	go func() {
+		v, err := g.DoContext(second, "octocat", func(ctx context.Context) (int, error) { return -1, nil })
+		errs <- err
+		results <- v
+	}()
+	for g.Coalesced() < 1 {
+		runtime.Gosched()
+	}
+
+	cancelFirst()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("The departed caller should get its ctx error, got %v", err)
+	}
+	select {
+	case <-cancelled:
+		t.Fatal("The call must keep running while another caller waits")
+	default:
+	}
+
+	close(release)
+	if err := <-errs; err != nil || <-results != 42 {
+		t.Errorf("The remaining caller should get the shared result, got %v", err)
+	}
+}
+
+func TestGroupCancelsAbandonedCall(t *testing.T) {
+	var g Group[string, int]
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	cancelled := make(chan struct{})
+	_, err := g.DoContext(ctx, "octocat", func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		close(cancelled)
+		return 0, ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the caller's deadline, got %v", err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("The upstream call should be cancelled once nobody waits for it")
+	}
+
+	// An abandoned call must not be joined by later callers
+	v, err := g.DoContext(context.Background(), "octocat", func(ctx context.Context) (int, error) { return 7, nil })
+	if err != nil || v != 7 {
+		t.Errorf("Expected a fresh execution to return 7, got %d (%v)", v, err)
+	}
+}
+
+func TestGroupPassesCallerValues(t *testing.T) {
+	var g Group[string, string]
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "user-token")
+
+	v, _ := g.DoContext(ctx, "octocat", func(ctx context.Context) (string, error) {
+		token, _ := ctx.Value(key{}).(string)
+		return token, nil
+	})
+	if v != "user-token" {
+		t.Errorf("The call should see the first caller's values, got %q", v)
+	}
+}
@@ -0,0 +1,18 @@
+package cache
+
+import (
+	"time"
+
+	"github-api/backend/internal/models"
+)
+
+// Manager is the type-independent administration surface of a string-keyed
+// cache, letting operators inspect and invalidate caches of any value type
+type Manager interface {
+	Keys() []models.CacheKeyInfo
+	Entry(key string) (*models.CacheEntry, bool)
+	Delete(key string) bool
+	DeletePrefix(prefix string) int
+	Configure(ttl time.Duration, maxSize int)
+	Stats() models.CacheStats
+}
@@ -0,0 +1,266 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github-api/backend/internal/models"
+)
+
+// storeTimeout bounds every call to the persistent store
+const storeTimeout = 2 * time.Second
+
+// Store is a persistent second-level cache backend holding raw JSON payloads
+type Store interface {
+	Load(ctx context.Context, kind, key string) (*models.CacheRecord, error)
+	LoadRecent(ctx context.Context, kind string, since time.Time, limit int) ([]models.CacheRecord, error)
+	Save(ctx context.Context, record *models.CacheRecord) error
+	Delete(ctx context.Context, kind, key string) error
+	DeletePrefix(ctx context.Context, kind, prefix string) error
+	Clear(ctx context.Context, kind string) error
+}
+
+// Tiered combines an in-memory LRU (L1) with an optional persistent Store (L2).
+// Reads fall through to L2 on an L1 miss and promote hits back into L1 with
+// their remaining TTL; writes go to L1 immediately and to L2 in the background.
+type Tiered[V any] struct {
+	l1     *Cache[string, V]
+	kind   string
+	store  Store
+	l2Hits atomic.Int64
+}
+
+// NewTiered wraps an L1 cache; kind namespaces its keys in the store
+func NewTiered[V any](l1 *Cache[string, V], kind string) *Tiered[V] {
+	return &Tiered[V]{l1: l1, kind: kind}
+}
+
+// SetStore attaches the persistent L2 store (call before serving traffic)
+func (t *Tiered[V]) SetStore(store Store) {
+	t.store = store
+}
+
+// L1 returns the in-memory tier
+func (t *Tiered[V]) L1() *Cache[string, V] {
+	return t.l1
+}
+
+// Get retrieves a fresh value from either tier.
+// An expired entry is kept in L1 so its validators can be used to revalidate it.
+func (t *Tiered[V]) Get(key string) (V, bool) {
+	value, _, found := t.lookup(key, false)
+	return value, found
+}
+
+// Lookup retrieves a value from either tier, including stale entries
+func (t *Tiered[V]) Lookup(key string) (value V, stale bool, found bool) {
+	return t.lookup(key, true)
+}
+
+func (t *Tiered[V]) lookup(key string, allowStale bool) (value V, stale bool, found bool) {
+	if value, stale, found = t.l1.lookup(key, allowStale); found || t.store == nil {
+		return value, stale, found
+	}
+	if _, retained := t.l1.Peek(key); retained {
+		// L1 holds an expired copy at least as recent as L2's
+		return value, false, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	record, err := t.store.Load(ctx, t.kind, key)
+	if err != nil {
+		log.Printf("⚠️ [Cache] L2 load failed for %s/%s: %v", t.kind, key, err)
+		return value, false, false
+	}
+	if record == nil {
+		return value, false, false
+	}
+
+	value, age, ok := t.promote(record)
+	if !ok {
+		return value, false, false
+	}
+
+	stale = age > t.l1.TTL()
+	if stale && !allowStale {
+		return value, false, false
+	}
+	t.l2Hits.Add(1)
+	return value, stale, true
+}
+
+// Set stores a value in L1 and persists it to L2 in the background
+func (t *Tiered[V]) Set(key string, value V) {
+	t.SetWithValidators(key, value, "", "")
+}
+
+// SetWithValidators stores a value together with the ETag and Last-Modified
+// values of the response it came from
+func (t *Tiered[V]) SetWithValidators(key string, value V, etag, lastModified string) {
+	t.l1.SetWithValidators(key, value, t.l1.TTL(), etag, lastModified)
+	t.persist(key, value, etag, lastModified)
+}
+
+// Revalidate returns the ETag and Last-Modified values of a live L1 entry
+// for a conditional request, counting a revalidation
+func (t *Tiered[V]) Revalidate(key string) (etag, lastModified string, ok bool) {
+	return t.l1.Revalidate(key)
+}
+
+// Touch restarts an entry's TTL after the upstream confirmed it unchanged,
+// returning the cached value without decoding anything
+func (t *Tiered[V]) Touch(key string) (V, bool) {
+	value, ok := t.l1.Touch(key)
+	if ok {
+		if entry, live := t.l1.Peek(key); live {
+			t.persist(key, value, entry.ETag, entry.LastModified)
+		}
+	}
+	return value, ok
+}
+
+// persist writes a value to L2 in the background
+func (t *Tiered[V]) persist(key string, value V, etag, lastModified string) {
+	if t.store == nil {
+		return
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("⚠️ [Cache] Failed to encode %s/%s: %v", t.kind, key, err)
+		return
+	}
+	record := &models.CacheRecord{
+		Kind:         t.kind,
+		Key:          key,
+		Data:         string(data),
+		FetchedAt:    time.Now(),
+		ETag:         etag,
+		LastModified: lastModified,
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+		defer cancel()
+		if err := t.store.Save(ctx, record); err != nil {
+			log.Printf("⚠️ [Cache] L2 save failed for %s/%s: %v", t.kind, key, err)
+		}
+	}()
+}
+
+// Delete removes a key from both tiers
+func (t *Tiered[V]) Delete(key string) bool {
+	deleted := t.l1.Delete(key)
+	if t.store != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+		defer cancel()
+		if err := t.store.Delete(ctx, t.kind, key); err != nil {
+			log.Printf("⚠️ [Cache] L2 delete failed for %s/%s: %v", t.kind, key, err)
+		}
+	}
+	return deleted
+}
+
+// DeletePrefix removes every key starting with prefix from both tiers,
+// returning how many L1 entries were removed
+func (t *Tiered[V]) DeletePrefix(prefix string) int {
+	removed := t.l1.DeleteFunc(func(key string) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+	if t.store != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+		defer cancel()
+		if err := t.store.DeletePrefix(ctx, t.kind, prefix); err != nil {
+			log.Printf("⚠️ [Cache] L2 prefix delete failed for %s/%s*: %v", t.kind, prefix, err)
+		}
+	}
+	return removed
+}
+
+// Keys describes every entry held in L1
+func (t *Tiered[V]) Keys() []models.CacheKeyInfo {
+	return t.l1.Keys()
+}
+
+// Entry returns an L1 entry with its metadata without affecting statistics
+func (t *Tiered[V]) Entry(key string) (*models.CacheEntry, bool) {
+	entry, ok := t.l1.Peek(key)
+	if !ok {
+		return nil, false
+	}
+	return &models.CacheEntry{CacheKeyInfo: entry.Info(time.Now()), Data: entry.Data}, true
+}
+
+// Configure changes the L1 TTL and maximum size at runtime; zero leaves a setting unchanged
+func (t *Tiered[V]) Configure(ttl time.Duration, maxSize int) {
+	if ttl > 0 {
+		t.l1.SetTTL(ttl)
+	}
+	if maxSize > 0 {
+		t.l1.Resize(maxSize)
+	}
+}
+
+// Clear empties both tiers
+func (t *Tiered[V]) Clear() {
+	t.l1.Clear()
+	t.l2Hits.Store(0)
+	if t.store != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+		defer cancel()
+		if err := t.store.Clear(ctx, t.kind); err != nil {
+			log.Printf("⚠️ [Cache] L2 clear failed for %s: %v", t.kind, err)
+		}
+	}
+}
+
+// Stats returns L1 statistics plus the number of L2 hits
+func (t *Tiered[V]) Stats() models.CacheStats {
+	stats := t.l1.Stats()
+	stats.PersistentHits = t.l2Hits.Load()
+	return stats
+}
+
+// Warm loads up to limit recent entries from L2 into L1, returning how many were loaded
+func (t *Tiered[V]) Warm(ctx context.Context, limit int) (int, error) {
+	if t.store == nil {
+		return 0, nil
+	}
+
+	records, err := t.store.LoadRecent(ctx, t.kind, time.Now().Add(-t.l1.MaxAge()), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	// Records are newest first; insert oldest first so the newest end up most recently used
+	loaded := 0
+	for i := len(records) - 1; i >= 0; i-- {
+		if _, _, ok := t.promote(&records[i]); ok {
+			loaded++
+		}
+	}
+	return loaded, nil
+}
+
+// promote decodes an L2 record into L1 as of when it was fetched, so it
+// keeps only its remaining TTL
+func (t *Tiered[V]) promote(record *models.CacheRecord) (value V, age time.Duration, ok bool) {
+	age = time.Since(record.FetchedAt)
+	if age > t.l1.MaxAge() {
+		return value, age, false
+	}
+
+	if err := json.Unmarshal([]byte(record.Data), &value); err != nil {
+		log.Printf("⚠️ [Cache] Failed to decode %s/%s: %v", t.kind, record.Key, err)
+		return value, age, false
+	}
+
+	t.l1.store(record.Key, value, record.FetchedAt, t.l1.TTL(), record.ETag, record.LastModified)
+	return value, age, true
+}
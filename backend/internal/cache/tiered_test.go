@@ -0,0 +1,182 @@
+// Package cache provides tests for the two-tier cache
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github-api/backend/internal/models"
+)
+
+// memoryStore is an in-memory Store used in place of PostgreSQL
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]models.CacheRecord
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: make(map[string]models.CacheRecord)}
+}
+
+func (m *memoryStore) Load(ctx context.Context, kind, key string) (*models.CacheRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := m.records[kind+"/"+key]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (m *memoryStore) LoadRecent(ctx context.Context, kind string, since time.Time, limit int) ([]models.CacheRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var records []models.CacheRecord
+	for _, record := range m.records {
+		if record.Kind == kind && record.FetchedAt.After(since) && len(records) < limit {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (m *memoryStore) Save(ctx context.Context, record *models.CacheRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records[record.Kind+"/"+record.Key] = *record
+	return nil
+}
+
+func (m *memoryStore) Delete(ctx context.Context, kind, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.records, kind+"/"+key)
+	return nil
+}
+
+func (m *memoryStore) DeletePrefix(ctx context.Context, kind, prefix string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, record := range m.records {
+		if record.Kind == kind && strings.HasPrefix(record.Key, prefix) {
+			delete(m.records, id)
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) Clear(ctx context.Context, kind string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = make(map[string]models.CacheRecord)
+	return nil
+}
+
+func TestTieredReadsThroughToStore(t *testing.T) {
+	store := newMemoryStore()
+	store.Save(context.Background(), &models.CacheRecord{
+		Kind: "user", Key: "octocat", Data: `{"login":"octocat","followers":10}`, FetchedAt: time.Now().Add(-time.Minute),
+	})
+
+	tiered := NewTiered(New[string, models.GitHubUser](10, 5*time.Minute), "user")
+	tiered.SetStore(store)
+
+	user, stale, found := tiered.Lookup("octocat")
+	if !found || stale || user.Followers != 10 {
+		t.Fatalf("Expected fresh L2 hit, got %+v (stale=%v, found=%v)", user, stale, found)
+	}
+	if tiered.L1().Size() != 1 {
+		t.Error("L2 hit should be promoted into L1")
+	}
+	if tiered.Stats().PersistentHits != 1 {
+		t.Errorf("Expected 1 persistent hit, got %d", tiered.Stats().PersistentHits)
+	}
+}
+
+func TestTieredIgnoresExpiredRecords(t *testing.T) {
+	store := newMemoryStore()
+	store.Save(context.Background(), &models.CacheRecord{
+		Kind: "user", Key: "octocat", Data: `{"login":"octocat"}`, FetchedAt: time.Now().Add(-2 * time.Hour),
+	})
+
+	l1 := New[string, models.GitHubUser](10, 5*time.Minute)
+	l1.SetStaleTTL(time.Hour)
+	tiered := NewTiered(l1, "user")
+	tiered.SetStore(store)
+
+	if _, _, found := tiered.Lookup("octocat"); found {
+		t.Error("Records older than the hard TTL should be ignored")
+	}
+}
+
+func TestTieredWarm(t *testing.T) {
+	store := newMemoryStore()
+	for _, name := range []string{"a", "b", "c"} {
+		store.Save(context.Background(), &models.CacheRecord{
+			Kind: "user", Key: name, Data: `{"login":"` + name + `"}`, FetchedAt: time.Now(),
+		})
+	}
+
+	tiered := NewTiered(New[string, models.GitHubUser](10, 5*time.Minute), "user")
+	tiered.SetStore(store)
+
+	loaded, err := tiered.Warm(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("Warm failed: %v", err)
+	}
+	if loaded != 3 || tiered.L1().Size() != 3 {
+		t.Errorf("Expected 3 warmed entries, loaded=%d size=%d", loaded, tiered.L1().Size())
+	}
+}
+
+func TestTieredDeletePrefix(t *testing.T) {
+	store := newMemoryStore()
+	tiered := NewTiered(New[string, int](10, time.Minute), "repos")
+	tiered.SetStore(store)
+
+	for key, value := range map[string]int{"octocat": 1, "octo-org": 2, "torvalds": 3} {
+		tiered.l1.Set(key, value)
+		store.Save(context.Background(), &models.CacheRecord{Kind: "repos", Key: key, Data: "1", FetchedAt: time.Now()})
+	}
+
+	if removed := tiered.DeletePrefix("octo"); removed != 2 {
+		t.Errorf("Expected 2 L1 entries removed, got %d", removed)
+	}
+	if len(store.records) != 1 {
+		t.Errorf("Expected 1 L2 record left, got %d", len(store.records))
+	}
+	if _, found := tiered.Entry("torvalds"); !found {
+		t.Error("Entries outside the prefix should be kept")
+	}
+}
+
+func TestTieredPersistsValidators(t *testing.T) {
+	store := newMemoryStore()
+	store.Save(context.Background(), &models.CacheRecord{
+		Kind: "user", Key: "octocat", Data: `{"login":"octocat"}`, FetchedAt: time.Now().Add(-10 * time.Minute),
+		ETag: `"abc"`, LastModified: "Mon, 01 Jan 2024 00:00:00 GMT",
+	})
+
+	l1 := New[string, models.GitHubUser](10, 5*time.Minute)
+	l1.SetStaleTTL(time.Hour)
+	tiered := NewTiered(l1, "user")
+	tiered.SetStore(store)
+
+	if _, found := tiered.Get("octocat"); found {
+		t.Fatal("Expired record should not be returned by Get")
+	}
+	etag, lastModified, ok := tiered.Revalidate("octocat")
+	if !ok || etag != `"abc"` || lastModified == "" {
+		t.Fatalf("Expected the record's validators after promotion, got %q %q (ok=%v)", etag, lastModified, ok)
+	}
+
+	if user, ok := tiered.Touch("octocat"); !ok || user.Login != "octocat" {
+		t.Fatalf("Touch should return the promoted user, got %+v (ok=%v)", user, ok)
+	}
+	if _, found := tiered.Get("octocat"); !found {
+		t.Error("Touched entry should be fresh")
+	}
+}
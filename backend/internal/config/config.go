@@ -3,6 +3,7 @@ package config
 
 import (
 	"os"
+	"strings"
 	"time"
 )
 
@@ -10,12 +11,18 @@ import (
 type Config struct {
 	ServerPort         string
 	CacheTTL           time.Duration
+	CacheStaleTTL      time.Duration
+	NegativeCacheTTL   time.Duration
 	MaxCacheSize       int
 	MaxBatchSize       int
+	MaxPages           int
+	MaxLanguageRepos   int
+	MaxConnectionPages int
 	GitHubAPIURL       string
 	Timeout            time.Duration
 	NvidiaAPIKey       string
 	GitHubToken        string
+	GitHubTokens       []string
 	DatabaseURL        string
 	GitHubClientID     string
 	GitHubClientSecret string
@@ -37,6 +44,11 @@ func Default() *Config {
 		frontendURL = "http://localhost:3000"
 	}
 
+	githubAPIURL := os.Getenv("GITHUB_API_URL")
+	if githubAPIURL == "" {
+		githubAPIURL = "https://api.github.com"
+	}
+
 	redirectURL := os.Getenv("GITHUB_REDIRECT_URL")
 	if redirectURL == "" {
 		redirectURL = "http://localhost:8000/api/auth/callback"
@@ -45,12 +57,18 @@ func Default() *Config {
 	return &Config{
 		ServerPort:         ":" + port,
 		CacheTTL:           5 * time.Minute,
+		CacheStaleTTL:      1 * time.Hour,
+		NegativeCacheTTL:   1 * time.Minute,
 		MaxCacheSize:       1000,
 		MaxBatchSize:       10,
-		GitHubAPIURL:       "https://api.github.com/users/",
+		MaxPages:           10,
+		MaxLanguageRepos:   50,
+		MaxConnectionPages: 5,
+		GitHubAPIURL:       githubAPIURL,
 		Timeout:            10 * time.Second,
 		NvidiaAPIKey:       os.Getenv("NVIDIA_API_KEY"),
 		GitHubToken:        os.Getenv("GITHUB_TOKEN"),
+		GitHubTokens:       splitList(os.Getenv("GITHUB_TOKENS")),
 		DatabaseURL:        os.Getenv("DATABASE_URL"),
 		GitHubClientID:     os.Getenv("GITHUB_CLIENT_ID"),
 		GitHubClientSecret: os.Getenv("GITHUB_CLIENT_SECRET"),
@@ -60,3 +78,14 @@ func Default() *Config {
 		DBConnMaxLifetime:  5 * time.Minute,
 	}
 }
+
+// splitList parses a comma-separated environment value, dropping blanks
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -84,6 +84,7 @@ func (db *DB) InitSchema(ctx context.Context) error {
 		preferred_language VARCHAR(50),
 		theme VARCHAR(20) DEFAULT 'system'
 	);
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT '';
 
 	-- User search history
 	CREATE TABLE IF NOT EXISTS search_history (
@@ -218,6 +219,18 @@ func (db *DB) InitSchema(ctx context.Context) error {
 	CREATE INDEX IF NOT EXISTS idx_devai_conversations_user_id ON devai_conversations(user_id);
 	CREATE INDEX IF NOT EXISTS idx_devai_conversations_updated_at ON devai_conversations(updated_at DESC);
 	CREATE INDEX IF NOT EXISTS idx_devai_messages_conversation_id ON devai_messages(conversation_id);
+
+	-- Second-level cache of raw GitHub API payloads (survives restarts)
+	CREATE TABLE IF NOT EXISTS github_cache (
+		kind VARCHAR(20) NOT NULL,
+		cache_key VARCHAR(255) NOT NULL,
+		data JSONB NOT NULL,
+		fetched_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (kind, cache_key)
+	);
+	CREATE INDEX IF NOT EXISTS idx_github_cache_fetched_at ON github_cache(kind, fetched_at DESC);
+	ALTER TABLE github_cache ADD COLUMN IF NOT EXISTS etag TEXT NOT NULL DEFAULT '';
+	ALTER TABLE github_cache ADD COLUMN IF NOT EXISTS last_modified TEXT NOT NULL DEFAULT '';
 	`
 
 	_, err := db.ExecContext(ctx, schema)
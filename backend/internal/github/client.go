@@ -0,0 +1,343 @@
+// Package github provides a shared client for the GitHub REST API
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github-api/backend/internal/breaker"
+	"github-api/backend/internal/models"
+	"github-api/backend/internal/retry"
+)
+
+// DefaultBaseURL is the public GitHub REST API endpoint
+const DefaultBaseURL = "https://api.github.com"
+
+const (
+	userAgent  = "DevScope-API"
+	apiVersion = "2022-11-28"
+	mediaType  = "application/vnd.github+json"
+
+	// GitHub is treated as down after breakerThreshold consecutive failed
+	// requests and is not called again for breakerCooldown
+	breakerThreshold = 5
+	breakerCooldown  = 30 * time.Second
+)
+
+// Client performs authenticated requests against the GitHub REST API.
+// A Client is safe for concurrent use; WithToken derives per-user clients
+// that share the same connection pool and rate-limit tracking.
+//
+// Each token's quota is read from GitHub's X-RateLimit-* headers. As a quota
+// nears zero requests are paced across the remaining window; once exhausted
+// (or after a secondary rate limit) requests queue briefly and then fail
+// fast with an *Error matching ErrRateLimited that carries the reset time.
+//
+// Idempotent requests failing with 502, 503, 504 or a dropped connection are
+// retried with jittered backoff (see retry.DefaultPolicy). While GitHub keeps
+// failing, a circuit breaker shared by all derived clients makes requests
+// fail fast with an error matching breaker.ErrOpen.
+type Client struct {
+	baseURL    string
+	token      string
+	pool       *tokenPool
+	httpClient *http.Client
+	limits     *rateTracker
+	retries    *retry.Transport
+	breaker    *breaker.Breaker
+}
+
+// NewClient creates a client for baseURL (DefaultBaseURL when empty).
+// An empty token sends unauthenticated requests.
+func NewClient(baseURL, token string, timeout time.Duration) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	retries := retry.NewTransport("github", retry.DefaultPolicy(), nil)
+	circuit := breaker.New("github", breakerThreshold, breakerCooldown)
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Transport: breaker.NewTransport(circuit, retries, timeout)},
+		limits:     newRateTracker(),
+		retries:    retries,
+		breaker:    circuit,
+	}
+}
+
+// NewPoolClient creates a client that spreads requests across several server
+// tokens, sending each one with whichever token has the most quota left.
+// A token GitHub rejects with 401 is dropped from rotation and the request
+// is retried with the next one; once none remain requests go out
+// unauthenticated.
+func NewPoolClient(baseURL string, tokens []string, timeout time.Duration) *Client {
+	client := NewClient(baseURL, "", timeout)
+	if pool := newTokenPool(tokens); len(pool.tokens) > 0 {
+		client.pool = pool
+	}
+	return client
+}
+
+// WithToken returns a client that authenticates with token instead.
+// The derived client never draws on the token pool.
+func (c *Client) WithToken(token string) *Client {
+	clone := *c
+	clone.token = token
+	clone.pool = nil
+	return &clone
+}
+
+// BaseURL returns the API root requests are sent to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// RateLimit reports the last known quota and usage of this client's token,
+// or of every pooled token
+func (c *Client) RateLimit() []models.RateLimitStatus {
+	if c.pool == nil {
+		return c.limits.status(c.token)
+	}
+
+	tokens, disabled := c.pool.snapshot()
+	var statuses []models.RateLimitStatus
+	for _, token := range tokens {
+		status := c.limits.status(token)
+		if len(status) == 0 {
+			status = []models.RateLimitStatus{{Token: maskToken(token), Resource: "core"}}
+		}
+		for i := range status {
+			status[i].Disabled = disabled[token]
+		}
+		statuses = append(statuses, status...)
+	}
+	return statuses
+}
+
+// Retries reports how often transient failures were retried
+func (c *Client) Retries() models.RetryStats {
+	return c.retries.Stats()
+}
+
+// Breaker reports the state of the circuit breaker guarding GitHub
+func (c *Client) Breaker() models.BreakerStatus {
+	return c.breaker.Status()
+}
+
+type tokenContextKey struct{}
+
+// contextToken is a token attached to a context and, when known, the login
+// of the account it belongs to
+type contextToken struct {
+	token string
+	login string
+}
+
+// ContextWithToken returns a copy of ctx that makes requests through a client
+// without its own token (such as the shared pool client) authenticate as
+// token, typically the signed-in user's OAuth token
+func ContextWithToken(ctx context.Context, token string) context.Context {
+	return ContextWithUserToken(ctx, token, "")
+}
+
+// ContextWithUserToken is like ContextWithToken for the token of the account
+// login, so that callers can tell lookups of that account from others
+func ContextWithUserToken(ctx context.Context, token, login string) context.Context {
+	if token == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, tokenContextKey{}, contextToken{token: token, login: login})
+}
+
+// ContextWithoutToken returns a copy of ctx whose requests use the client's
+// own credentials even if a token was attached further up. Use it for
+// responses that are shared between users but would include private data
+// with the attached token.
+func ContextWithoutToken(ctx context.Context) context.Context {
+	if TokenFromContext(ctx) == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, tokenContextKey{}, contextToken{})
+}
+
+// TokenFromContext returns the token attached with ContextWithToken, if any
+func TokenFromContext(ctx context.Context) string {
+	attached, _ := ctx.Value(tokenContextKey{}).(contextToken)
+	return attached.token
+}
+
+// TokenLogin returns the login of the account whose token was attached with
+// ContextWithUserToken, or "" if there is none or it is unknown
+func TokenLogin(ctx context.Context) string {
+	attached, _ := ctx.Value(tokenContextKey{}).(contextToken)
+	return attached.login
+}
+
+// NewRequest builds a request for path, which may be relative to the base URL
+// or an absolute URL. A non-nil body is encoded as JSON.
+func (c *Client) NewRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.resolve(path), reader)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("Accept", mediaType)
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return req, nil
+}
+
+// Do sends req and decodes a successful response body into out (if non-nil).
+// Non-2xx responses are returned as *Error. The response is returned with its
+// body closed so callers can inspect headers such as Link.
+//
+// On a client without its own token, a token attached to the request context
+// with ContextWithToken is used first. If GitHub rejects it or its quota is
+// spent, the request falls back to the shared token pool.
+func (c *Client) Do(req *http.Request, out interface{}) (*http.Response, error) {
+	if c.token == "" {
+		if token := TokenFromContext(req.Context()); token != "" {
+			resp, err := c.do(authorize(req, token), token, out)
+			if !errors.Is(err, ErrUnauthorized) && !errors.Is(err, ErrRateLimited) || !rewind(req) {
+				return resp, err
+			}
+		}
+	}
+
+	if c.pool == nil {
+		return c.do(req, c.token, out)
+	}
+
+	for {
+		token := c.pool.pick(c.limits, resourceFor(req.URL.Path), time.Now())
+		resp, err := c.do(authorize(req, token), token, out)
+		if token == "" || !errors.Is(err, ErrUnauthorized) {
+			return resp, err
+		}
+		if c.pool.disable(token) {
+			log.Printf("⚠️ [GitHub] Token %s rejected with 401, removed from rotation", maskToken(token))
+		}
+
+		// Retry with the next token if the body can be replayed
+		if !rewind(req) {
+			return resp, err
+		}
+	}
+}
+
+// authorize returns req authenticated as token (req itself if token is empty)
+func authorize(req *http.Request, token string) *http.Request {
+	if token == "" {
+		return req
+	}
+	attempt := req.Clone(req.Context())
+	attempt.Header.Set("Authorization", "Bearer "+token)
+	return attempt
+}
+
+// rewind resets req's body so it can be sent again, reporting whether it could
+func rewind(req *http.Request) bool {
+	if req.Body == nil || req.Body == http.NoBody {
+		return true
+	}
+	if req.GetBody == nil {
+		return false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return false
+	}
+	req.Body = body
+	return true
+}
+
+// do sends req on behalf of token, which selects the quota it is tracked against
+func (c *Client) do(req *http.Request, token string, out interface{}) (*http.Response, error) {
+	key := quotaKey{token: token, resource: resourceFor(req.URL.Path)}
+	if err := c.limits.wait(req.Context(), key); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	c.limits.update(key, resp.Header)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := newError(resp)
+		if apiErr.RateLimited {
+			c.limits.block(key, apiErr)
+		}
+		return resp, apiErr
+	}
+
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp, fmt.Errorf("error parsing JSON: %w", err)
+		}
+	}
+	return resp, nil
+}
+
+// Get fetches path and decodes the JSON response into out
+func (c *Client) Get(ctx context.Context, path string, out interface{}) (*http.Response, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req, out)
+}
+
+// Send issues a request with a JSON body (may be nil) and decodes the response into out
+func (c *Client) Send(ctx context.Context, method, path string, body, out interface{}) (*http.Response, error) {
+	req, err := c.NewRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req, out)
+}
+
+// GetJSON fetches path and decodes the response into a new T
+func GetJSON[T any](ctx context.Context, c *Client, path string) (*T, error) {
+	var result T
+	if _, err := c.Get(ctx, path, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) resolve(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return c.baseURL + path
+}
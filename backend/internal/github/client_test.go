@@ -0,0 +1,247 @@
+// Package github provides tests for the GitHub API client
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github-api/backend/internal/breaker"
+)
+
+func newTestClient(t *testing.T, token string, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewClient(server.URL, token, 5*time.Second)
+}
+
+func TestClientSetsHeaders(t *testing.T) {
+	client := newTestClient(t, "shared", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer user-token" {
+			t.Errorf("Expected user token, got %q", got)
+		}
+		if r.Header.Get("User-Agent") != userAgent || r.Header.Get("Accept") != mediaType {
+			t.Errorf("Missing standard headers: %v", r.Header)
+		}
+		if r.URL.Path != "/users/octocat" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"login":"octocat"}`))
+	})
+
+	user, err := GetJSON[struct {
+		Login string `json:"login"`
+	}](context.Background(), client.WithToken("user-token"), "/users/octocat")
+	if err != nil {
+		t.Fatalf("GetJSON failed: %v", err)
+	}
+	if user.Login != "octocat" {
+		t.Errorf("Expected octocat, got %q", user.Login)
+	}
+}
+
+func TestClientUnauthenticated(t *testing.T) {
+	client := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Error("No Authorization header should be sent without a token")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := client.Get(context.Background(), "/zen", nil); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestClientTypedErrors(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		target  error
+	}{
+		{
+			name:    "not found",
+			handler: func(w http.ResponseWriter, r *http.Request) { http.NotFound(w, r) },
+			target:  ErrNotFound,
+		},
+		{
+			name: "unauthorized",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"message":"Bad credentials"}`))
+			},
+			target: ErrUnauthorized,
+		},
+		{
+			name: "primary rate limit",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+				w.WriteHeader(http.StatusForbidden)
+			},
+			target: ErrRateLimited,
+		},
+		{
+			name: "secondary rate limit",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "30")
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			target: ErrRateLimited,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, "", tt.handler)
+			_, err := client.Get(context.Background(), "/users/ghost", nil)
+			if !errors.Is(err, tt.target) {
+				t.Fatalf("Expected %v, got %v", tt.target, err)
+			}
+
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected *Error, got %T", err)
+			}
+			if tt.target == ErrRateLimited && apiErr.Reset.IsZero() {
+				t.Error("Rate limit errors should carry the reset time")
+			}
+			if tt.name == "primary rate limit" && !apiErr.Reset.Equal(reset) {
+				t.Errorf("Expected reset %v, got %v", reset, apiErr.Reset)
+			}
+		})
+	}
+}
+
+func TestForbiddenIsNotRateLimited(t *testing.T) {
+	client := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"Resource not accessible by integration"}`))
+	})
+
+	_, err := client.Get(context.Background(), "/repos/o/r", nil)
+	if errors.Is(err, ErrRateLimited) {
+		t.Error("A plain 403 should not be reported as rate limited")
+	}
+	if StatusCode(err) != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", StatusCode(err))
+	}
+}
+
+func TestSendEncodesBody(t *testing.T) {
+	client := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["state"] != "read" {
+			t.Errorf("Unexpected body %v (err=%v)", body, err)
+		}
+		if r.Method != http.MethodPatch {
+			t.Errorf("Expected PATCH, got %s", r.Method)
+		}
+		w.WriteHeader(http.StatusResetContent)
+	})
+
+	if _, err := client.Send(context.Background(), http.MethodPatch, "/notifications/threads/1", map[string]string{"state": "read"}, nil); err != nil {
+		t.Errorf("Send failed: %v", err)
+	}
+}
+
+func TestResolveAbsoluteURL(t *testing.T) {
+	client := NewClient("https://example.test/api/", "", time.Second)
+	if got := client.resolve("users/a"); got != "https://example.test/api/users/a" {
+		t.Errorf("Unexpected relative resolution %q", got)
+	}
+	if got := client.resolve("https://other.test/next?page=2"); got != "https://other.test/next?page=2" {
+		t.Errorf("Absolute URLs should be used as-is, got %q", got)
+	}
+}
+
+func TestGetConditional(t *testing.T) {
+	client := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` && r.Header.Get("If-Modified-Since") != "" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+		w.Write([]byte(`{"login":"octocat"}`))
+	})
+
+	var user struct {
+		Login string `json:"login"`
+	}
+	resp, notModified, err := client.GetConditional(context.Background(), "/users/octocat", Validators{}, &user)
+	if err != nil || notModified || user.Login != "octocat" {
+		t.Fatalf("Expected a full response, got %+v (notModified=%v, err=%v)", user, notModified, err)
+	}
+
+	validators := ValidatorsFrom(resp)
+	if validators.ETag != `"v1"` || validators.LastModified == "" {
+		t.Fatalf("Expected validators from the response, got %+v", validators)
+	}
+
+	_, notModified, err = client.GetConditional(context.Background(), "/users/octocat", validators, nil)
+	if err != nil || !notModified {
+		t.Errorf("Expected 304 Not Modified, got notModified=%v err=%v", notModified, err)
+	}
+}
+
+func TestClientRetriesTransientErrors(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"login":"octocat"}`))
+	})
+
+	user, err := GetJSON[struct {
+		Login string `json:"login"`
+	}](context.Background(), client, "/users/octocat")
+	if err != nil {
+		t.Fatalf("Expected the 502 to be retried: %v", err)
+	}
+	if user.Login != "octocat" || calls.Load() != 2 {
+		t.Errorf("Expected octocat on the second attempt, got %q after %d calls", user.Login, calls.Load())
+	}
+	if stats := client.Retries(); stats.Upstream != "github" || stats.Retries != 1 || stats.Recovered != 1 {
+		t.Errorf("Unexpected retry stats %+v", stats)
+	}
+	if status := client.RateLimit(); len(status) != 1 || status[0].Requests != 1 {
+		t.Errorf("A retried request should count once against the quota, got %+v", status)
+	}
+}
+
+func TestClientBreakerFailsFast(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	for i := 0; i < breakerThreshold; i++ {
+		client.Get(context.Background(), "/users/octocat", nil)
+	}
+	_, err := client.WithToken("user-token").Get(context.Background(), "/users/octocat", nil)
+
+	if !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("Expected ErrOpen once GitHub keeps failing, got %v", err)
+	}
+	if calls.Load() != breakerThreshold {
+		t.Errorf("Expected %d calls before the breaker opened, got %d", breakerThreshold, calls.Load())
+	}
+	if status := client.Breaker(); status.State != "open" || status.Rejected != 1 {
+		t.Errorf("Unexpected breaker status %+v", status)
+	}
+}
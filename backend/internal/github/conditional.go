@@ -0,0 +1,57 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Validators are the cache validators of a GitHub response. Sending them
+// back makes GitHub answer 304 Not Modified, which does not count against
+// the rate limit, when the resource is unchanged.
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// ValidatorsFrom reads the ETag and Last-Modified headers of a response
+func ValidatorsFrom(resp *http.Response) Validators {
+	if resp == nil {
+		return Validators{}
+	}
+	return Validators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+}
+
+// IsZero reports whether no validator is set
+func (v Validators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// apply adds the conditional request headers for v to req
+func (v Validators) apply(req *http.Request) {
+	if v.ETag != "" {
+		req.Header.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		req.Header.Set("If-Modified-Since", v.LastModified)
+	}
+}
+
+// GetConditional fetches path like Get, revalidating with v. When GitHub
+// answers 304 Not Modified, notModified is true and out is left untouched.
+func (c *Client) GetConditional(ctx context.Context, path string, v Validators, out interface{}) (resp *http.Response, notModified bool, err error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	v.apply(req)
+
+	resp, err = c.Do(req, out)
+	if errors.Is(err, ErrNotModified) {
+		return resp, true, nil
+	}
+	return resp, false, err
+}
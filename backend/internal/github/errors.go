@@ -0,0 +1,114 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sentinel errors matched with errors.Is against an *Error
+var (
+	ErrNotFound     = errors.New("github: resource not found")
+	ErrUnauthorized = errors.New("github: bad credentials")
+	ErrRateLimited  = errors.New("github: rate limit exceeded")
+	ErrNotModified  = errors.New("github: not modified")
+)
+
+// Error describes a non-2xx response from the GitHub API
+type Error struct {
+	StatusCode int
+	Message    string
+
+	// RateLimited reports a primary or secondary rate limit; Reset is when
+	// requests may resume (zero if GitHub did not say)
+	RateLimited bool
+	Reset       time.Time
+}
+
+func (e *Error) Error() string {
+	if e.RateLimited {
+		if !e.Reset.IsZero() {
+			return fmt.Sprintf("GitHub API rate limit exceeded (resets at %s)", e.Reset.Format(time.RFC3339))
+		}
+		return "GitHub API rate limit exceeded"
+	}
+	if e.Message != "" {
+		return fmt.Sprintf("GitHub API returned status: %d (%s)", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("GitHub API returned status: %d", e.StatusCode)
+}
+
+// Is matches the package sentinel errors
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrRateLimited:
+		return e.RateLimited
+	case ErrNotModified:
+		return e.StatusCode == http.StatusNotModified
+	}
+	return false
+}
+
+// StatusCode returns the HTTP status of a GitHub API error, or 0 if err is not one
+func StatusCode(err error) int {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// RateLimitReset returns when a rate-limit error lifts, or the zero time if
+// err is not a rate-limit error or GitHub did not say
+func RateLimitReset(err error) time.Time {
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.RateLimited {
+		return apiErr.Reset
+	}
+	return time.Time{}
+}
+
+// newError builds an *Error from a failed response, reading at most 4KB of the body
+func newError(resp *http.Response) *Error {
+	apiErr := &Error{StatusCode: resp.StatusCode}
+
+	var payload struct {
+		Message string `json:"message"`
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if json.Unmarshal(body, &payload) == nil {
+		apiErr.Message = payload.Message
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		apiErr.RateLimited = true
+	case resp.StatusCode == http.StatusForbidden:
+		apiErr.RateLimited = resp.Header.Get("X-RateLimit-Remaining") == "0" ||
+			strings.Contains(strings.ToLower(apiErr.Message), "rate limit")
+	}
+	if apiErr.RateLimited {
+		apiErr.Reset = resetTime(resp.Header)
+	}
+	return apiErr
+}
+
+// resetTime reads when a rate limit lifts from Retry-After or X-RateLimit-Reset
+func resetTime(header http.Header) time.Time {
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil {
+		return time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+	if epoch, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		return time.Unix(epoch, 0)
+	}
+	return time.Time{}
+}
@@ -0,0 +1,75 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github-api/backend/internal/retry"
+)
+
+// GraphQLError is an error GitHub reported in the body of a GraphQL response
+type GraphQLError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+func (e *GraphQLError) Error() string {
+	if e.Type != "" {
+		return fmt.Sprintf("GitHub GraphQL error: %s (%s)", e.Message, e.Type)
+	}
+	return "GitHub GraphQL error: " + e.Message
+}
+
+// Is matches ErrNotFound and ErrRateLimited
+func (e *GraphQLError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Type == "NOT_FOUND"
+	case ErrRateLimited:
+		return e.Type == "RATE_LIMITED"
+	}
+	return false
+}
+
+// GraphQL runs a read-only GraphQL v4 query and decodes its data into out.
+// Queries are retried on transient failures like GET requests. GitHub only
+// serves GraphQL to authenticated callers, so without a token the call fails
+// with an error matching ErrUnauthorized. Errors reported in the response
+// body are returned as *GraphQLError.
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	body := map[string]interface{}{"query": query, "variables": variables}
+	req, err := c.NewRequest(retry.WithIdempotent(ctx), http.MethodPost, c.graphqlURL(), body)
+	if err != nil {
+		return err
+	}
+
+	var payload struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []GraphQLError  `json:"errors"`
+	}
+	if _, err := c.Do(req, &payload); err != nil {
+		return err
+	}
+	if len(payload.Errors) > 0 {
+		return &payload.Errors[0]
+	}
+
+	if out != nil && len(payload.Data) > 0 {
+		if err := json.Unmarshal(payload.Data, out); err != nil {
+			return fmt.Errorf("error parsing JSON: %w", err)
+		}
+	}
+	return nil
+}
+
+// graphqlURL returns the GraphQL endpoint for the REST base URL. GitHub
+// Enterprise serves REST under /api/v3 and GraphQL under /api/graphql.
+func (c *Client) graphqlURL() string {
+	if base, ok := strings.CutSuffix(c.baseURL, "/api/v3"); ok {
+		return base + "/api/graphql"
+	}
+	return c.baseURL + "/graphql"
+}
@@ -0,0 +1,87 @@
+// Package github provides tests for GraphQL queries
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGraphQLDecodesData(t *testing.T) {
+	client := newTestClient(t, "token", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/graphql" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			Query     string            `json:"query"`
+			Variables map[string]string `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Query == "" || body.Variables["login"] != "octocat" {
+			t.Errorf("Unexpected body %+v", body)
+		}
+		w.Write([]byte(`{"data":{"user":{"name":"The Octocat"}}}`))
+	})
+
+	var data struct {
+		User struct {
+			Name string `json:"name"`
+		} `json:"user"`
+	}
+	err := client.GraphQL(context.Background(), `query($login: String!) { user(login: $login) { name } }`, map[string]interface{}{"login": "octocat"}, &data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if data.User.Name != "The Octocat" {
+		t.Errorf("Expected The Octocat, got %q", data.User.Name)
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	client := newTestClient(t, "token", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"user":null},"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a User with the login of 'ghost'."}]}`))
+	})
+
+	err := client.GraphQL(context.Background(), `query { user(login: "ghost") { name } }`, nil, nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+	var gqlErr *GraphQLError
+	if !errors.As(err, &gqlErr) || gqlErr.Type != "NOT_FOUND" {
+		t.Errorf("Expected a *GraphQLError, got %T", err)
+	}
+}
+
+func TestGraphQLRetriedAsIdempotent(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, "token", func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"data":{}}`))
+	})
+
+	if err := client.GraphQL(context.Background(), `query { viewer { login } }`, nil, nil); err != nil {
+		t.Fatalf("Expected the query to be retried: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestGraphQLURL(t *testing.T) {
+	tests := map[string]string{
+		"https://api.github.com":            "https://api.github.com/graphql",
+		"https://github.example.com/api/v3": "https://github.example.com/api/graphql",
+	}
+	for base, want := range tests {
+		if got := NewClient(base, "", 0).graphqlURL(); got != want {
+			t.Errorf("graphqlURL(%s) = %s, want %s", base, got, want)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Paginator walks a paginated listing, following the Link header's
+// rel="next" URL from one page to the next
+type Paginator[T any] struct {
+	client     *Client
+	next       string
+	pages      int
+	validators Validators
+}
+
+// NewPaginator starts a listing at path (which should set per_page)
+func NewPaginator[T any](c *Client, path string) *Paginator[T] {
+	return &Paginator[T]{client: c, next: path}
+}
+
+// HasNext reports whether another page is available
+func (p *Paginator[T]) HasNext() bool {
+	return p.next != ""
+}
+
+// Pages returns how many pages have been fetched so far
+func (p *Paginator[T]) Pages() int {
+	return p.pages
+}
+
+// Validators returns the validators of the first page: any change to a
+// listing sorted by recency shows up there, so they stand for the whole listing
+func (p *Paginator[T]) Validators() Validators {
+	return p.validators
+}
+
+// Revalidate makes the first page a conditional request with v. If GitHub
+// reports the listing unchanged, Next returns an error matching ErrNotModified.
+func (p *Paginator[T]) Revalidate(v Validators) *Paginator[T] {
+	p.validators = v
+	return p
+}
+
+// Next fetches the next page
+func (p *Paginator[T]) Next(ctx context.Context) ([]T, error) {
+	req, err := p.client.NewRequest(ctx, http.MethodGet, p.next, nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.pages == 0 {
+		p.validators.apply(req)
+	}
+
+	var items []T
+	resp, err := p.client.Do(req, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pages == 0 {
+		p.validators = ValidatorsFrom(resp)
+	}
+	p.pages++
+	p.next = ParseLinks(resp.Header.Get("Link"))["next"]
+	return items, nil
+}
+
+// ParseLinks parses a Link header into a map from rel to URL.
+// Format: <url?page=2>; rel="next", <url?page=10>; rel="last"
+func ParseLinks(link string) map[string]string {
+	links := make(map[string]string)
+	for _, part := range strings.Split(link, ",") {
+		segments := strings.Split(part, ";")
+		target := strings.TrimSpace(segments[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		for _, param := range segments[1:] {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && name == "rel" {
+				links[strings.Trim(value, `"`)] = target[1 : len(target)-1]
+			}
+		}
+	}
+	return links
+}
+
+// LastPage returns the page number of a Link header's rel="last" URL: the
+// total item count of a per_page=1 listing. A missing header means no items,
+// and a header without rel="last" means a single page.
+func LastPage(link string) int {
+	if link == "" {
+		return 0
+	}
+
+	last, ok := ParseLinks(link)["last"]
+	if !ok {
+		return 1
+	}
+
+	u, err := url.Parse(last)
+	if err != nil {
+		return 1
+	}
+	page, err := strconv.Atoi(u.Query().Get("page"))
+	if err != nil {
+		return 1
+	}
+	return page
+}
@@ -0,0 +1,68 @@
+// Package github provides tests for Link header pagination
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestParseLinks(t *testing.T) {
+	links := ParseLinks(`<https://api.github.com/user/repos?page=2>; rel="next", <https://api.github.com/user/repos?page=5>; rel="last"`)
+	if links["next"] != "https://api.github.com/user/repos?page=2" {
+		t.Errorf("Unexpected next link %q", links["next"])
+	}
+	if links["last"] != "https://api.github.com/user/repos?page=5" {
+		t.Errorf("Unexpected last link %q", links["last"])
+	}
+	if len(ParseLinks("")) != 0 {
+		t.Error("An empty header should have no links")
+	}
+}
+
+func TestLastPage(t *testing.T) {
+	tests := []struct {
+		link string
+		want int
+	}{
+		{"", 0},
+		{`<https://api.github.com/user/starred?per_page=1&page=2>; rel="next"`, 1},
+		{`<https://api.github.com/user/starred?per_page=1&page=2>; rel="next", <https://api.github.com/user/starred?per_page=1&page=42>; rel="last"`, 42},
+	}
+	for _, tt := range tests {
+		if got := LastPage(tt.link); got != tt.want {
+			t.Errorf("LastPage(%q) = %d, want %d", tt.link, got, tt.want)
+		}
+	}
+}
+
+func TestPaginatorFollowsNext(t *testing.T) {
+	var base string
+	client := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		if page < 3 {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=%d>; rel="next"`, base, page+1))
+		}
+		fmt.Fprintf(w, `[%d, %d]`, page*10, page*10+1)
+	})
+	base = client.BaseURL()
+
+	pages := NewPaginator[int](client, "/items")
+	var items []int
+	for pages.HasNext() {
+		page, err := pages.Next(context.Background())
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		items = append(items, page...)
+	}
+
+	if pages.Pages() != 3 || len(items) != 6 || items[5] != 31 {
+		t.Errorf("Expected 3 pages of 2 items, got %d pages: %v", pages.Pages(), items)
+	}
+}
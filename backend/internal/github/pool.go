@@ -0,0 +1,75 @@
+package github
+
+import (
+	"sync"
+	"time"
+)
+
+// tokenPool rotates shared server tokens, always preferring the one with the
+// most quota left. Tokens GitHub rejects with 401 are taken out of rotation.
+type tokenPool struct {
+	mu       sync.Mutex
+	tokens   []string
+	disabled map[string]bool
+}
+
+func newTokenPool(tokens []string) *tokenPool {
+	pool := &tokenPool{disabled: make(map[string]bool)}
+	seen := make(map[string]bool)
+	for _, token := range tokens {
+		if token == "" || seen[token] {
+			continue
+		}
+		seen[token] = true
+		pool.tokens = append(pool.tokens, token)
+	}
+	return pool
+}
+
+// pick returns the active token with the most remaining quota for resource.
+// Tokens with no known quota count as full. When every token is exhausted
+// the one that resets first is returned; with no active tokens left pick
+// returns "" and the request goes out unauthenticated.
+func (p *tokenPool) pick(t *rateTracker, resource string, now time.Time) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var best string
+	bestRemaining := -1
+	var bestReset time.Time
+	for _, token := range p.tokens {
+		if p.disabled[token] {
+			continue
+		}
+		remaining, reset := t.available(quotaKey{token: token, resource: resource}, now)
+		switch {
+		case best == "",
+			remaining > bestRemaining,
+			remaining < 0 && bestRemaining < 0 && reset.Before(bestReset):
+			best, bestRemaining, bestReset = token, remaining, reset
+		}
+	}
+	return best
+}
+
+// disable takes token out of rotation, reporting whether it was active
+func (p *tokenPool) disable(token string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.disabled[token] {
+		return false
+	}
+	p.disabled[token] = true
+	return true
+}
+
+// snapshot returns every token in configuration order with its disabled flag
+func (p *tokenPool) snapshot() ([]string, map[string]bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	disabled := make(map[string]bool, len(p.disabled))
+	for token, off := range p.disabled {
+		disabled[token] = off
+	}
+	return append([]string(nil), p.tokens...), disabled
+}
@@ -0,0 +1,161 @@
+// Package github provides tests for token pool rotation
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestPoolClient(t *testing.T, tokens []string, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewPoolClient(server.URL, tokens, 5*time.Second)
+}
+
+func TestPoolPrefersTokenWithMostQuota(t *testing.T) {
+	remaining := map[string]int{"token-a": 100, "token-b": 4000}
+	var mu sync.Mutex
+	var used []string
+
+	client := newTestPoolClient(t, []string{"token-a", "token-b"}, func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		mu.Lock()
+		used = append(used, token)
+		mu.Unlock()
+		quotaHeaders(w, 5000, remaining[token], time.Now().Add(time.Hour))
+		w.Write([]byte(`{}`))
+	})
+
+	// Both tokens are unknown at first, so prime each one
+	for i := 0; i < 2; i++ {
+		if _, err := client.Get(context.Background(), "/users/octocat", nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		client.Get(context.Background(), "/users/octocat", nil)
+	}
+
+	if used[0] != "token-a" || used[1] != "token-b" {
+		t.Fatalf("Expected unknown tokens to be tried in order, got %v", used)
+	}
+	for _, token := range used[2:] {
+		if token != "token-b" {
+			t.Errorf("Expected the token with more quota, got %v", used)
+			break
+		}
+	}
+}
+
+func TestPoolDropsRejectedToken(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+
+	client := newTestPoolClient(t, []string{"revoked", "valid", "valid"}, func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		mu.Lock()
+		calls[token]++
+		mu.Unlock()
+		if token == "revoked" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Get(context.Background(), "/users/octocat", nil); err != nil {
+			t.Fatalf("Request %d should succeed with the remaining token: %v", i, err)
+		}
+	}
+	if calls["revoked"] != 1 || calls["valid"] != 3 {
+		t.Errorf("Expected the revoked token to be tried once, got %v", calls)
+	}
+
+	status := client.RateLimit()
+	if len(status) != 2 {
+		t.Fatalf("Expected one row per distinct token, got %+v", status)
+	}
+	if !status[0].Disabled || status[1].Disabled {
+		t.Errorf("Only the revoked token should be disabled, got %+v", status)
+	}
+	if status[1].Requests != 3 {
+		t.Errorf("Expected 3 requests on the valid token, got %d", status[1].Requests)
+	}
+}
+
+func TestPoolFallsBackToAnonymous(t *testing.T) {
+	client := newTestPoolClient(t, []string{"revoked"}, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+
+	if _, err := client.Get(context.Background(), "/users/octocat", nil); err != nil {
+		t.Errorf("Expected an unauthenticated retry once every token is rejected: %v", err)
+	}
+}
+
+func TestWithTokenBypassesPool(t *testing.T) {
+	client := newTestPoolClient(t, []string{"shared"}, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer user-token" {
+			t.Errorf("Expected the user's token, got %q", got)
+		}
+		w.Write([]byte(`{}`))
+	})
+
+	client.WithToken("user-token").Get(context.Background(), "/user", nil)
+}
+
+func TestContextTokenLogin(t *testing.T) {
+	ctx := ContextWithUserToken(context.Background(), "user-token", "octocat")
+	if TokenFromContext(ctx) != "user-token" || TokenLogin(ctx) != "octocat" {
+		t.Fatalf("Expected octocat's token, got %q for %q", TokenFromContext(ctx), TokenLogin(ctx))
+	}
+	if login := TokenLogin(ContextWithToken(context.Background(), "token")); login != "" {
+		t.Errorf("A token attached without a login should have none, got %q", login)
+	}
+
+	stripped := ContextWithoutToken(ctx)
+	if TokenFromContext(stripped) != "" || TokenLogin(stripped) != "" {
+		t.Errorf("Dropping the token should drop its login too, got %q for %q", TokenFromContext(stripped), TokenLogin(stripped))
+	}
+}
+
+func TestContextTokenPreferredOverPool(t *testing.T) {
+	var mu sync.Mutex
+	var used []string
+	client := newTestPoolClient(t, []string{"shared"}, func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		mu.Lock()
+		used = append(used, token)
+		mu.Unlock()
+		if token == "expired" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+
+	client.Get(ContextWithToken(context.Background(), "user-token"), "/repos/me/private", nil)
+	if _, err := client.Get(ContextWithToken(context.Background(), "expired"), "/users/octocat", nil); err != nil {
+		t.Fatalf("Expected a fallback to the shared token: %v", err)
+	}
+	client.Get(context.Background(), "/users/octocat", nil)
+
+	want := []string{"user-token", "expired", "shared", "shared"}
+	if strings.Join(used, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected tokens %v, got %v", want, used)
+	}
+	if status := client.RateLimit(); len(status) != 1 || status[0].Disabled {
+		t.Errorf("A rejected user token should not affect the pool, got %+v", status)
+	}
+}
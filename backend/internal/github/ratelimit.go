@@ -0,0 +1,244 @@
+package github
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github-api/backend/internal/models"
+)
+
+const (
+	// lowQuotaFraction is the share of a token's limit below which requests are paced
+	lowQuotaFraction = 0.1
+
+	// maxPacingDelay caps the delay added to a single request while pacing
+	maxPacingDelay = 2 * time.Second
+
+	// maxQuotaWait is how long a request will queue for an exhausted quota
+	// before failing fast with a rate-limit error
+	maxQuotaWait = 5 * time.Second
+
+	// secondaryLimitBackoff applies when GitHub signals a secondary rate limit
+	// without a Retry-After header
+	secondaryLimitBackoff = time.Minute
+)
+
+// quotaKey identifies one token's quota for one API resource (core, search, graphql)
+type quotaKey struct {
+	token    string
+	resource string
+}
+
+// quota is the last known state of a quota, adjusted locally between responses
+type quota struct {
+	limit        int
+	remaining    int
+	used         int
+	reset        time.Time
+	blockedUntil time.Time
+
+	// requests counts responses received for this quota by this process
+	requests int64
+}
+
+// rateTracker records GitHub quotas per token and resource. It is shared by
+// every client derived from the same NewClient call.
+type rateTracker struct {
+	mu     sync.Mutex
+	quotas map[quotaKey]*quota
+}
+
+func newRateTracker() *rateTracker {
+	return &rateTracker{quotas: make(map[quotaKey]*quota)}
+}
+
+// resourceFor infers which GitHub quota a request path draws from
+func resourceFor(path string) string {
+	switch {
+	case strings.Contains(path, "/search/"):
+		return "search"
+	case strings.HasSuffix(path, "/graphql"):
+		return "graphql"
+	}
+	return "core"
+}
+
+// reserve decides whether a request may be sent now. It returns how long to
+// wait first and, if the quota is exhausted or blocked, when it resets.
+// Allowed requests are counted against the remaining quota immediately so
+// concurrent callers do not all spend the last few requests.
+func (t *rateTracker) reserve(key quotaKey, now time.Time) (wait time.Duration, reset time.Time, exhausted bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	q := t.quotas[key]
+	if q == nil {
+		return 0, time.Time{}, false
+	}
+
+	if now.Before(q.blockedUntil) {
+		return q.blockedUntil.Sub(now), q.blockedUntil, true
+	}
+	if !now.Before(q.reset) {
+		// The window has rolled over; the next response refreshes the quota
+		return 0, time.Time{}, false
+	}
+	if q.remaining <= 0 {
+		return q.reset.Sub(now), q.reset, true
+	}
+
+	if float64(q.remaining) < float64(q.limit)*lowQuotaFraction {
+		// Spread the remaining requests evenly over the rest of the window
+		wait = min(q.reset.Sub(now)/time.Duration(q.remaining+1), maxPacingDelay)
+	}
+	q.remaining--
+	q.used++
+	return wait, time.Time{}, false
+}
+
+// available reports how many requests key can make right now (math.MaxInt
+// if unknown or the window has rolled over), or -1 and when it frees up
+func (t *rateTracker) available(key quotaKey, now time.Time) (int, time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	q := t.quotas[key]
+	switch {
+	case q == nil:
+		return math.MaxInt, time.Time{}
+	case now.Before(q.blockedUntil):
+		return -1, q.blockedUntil
+	case !now.Before(q.reset):
+		return math.MaxInt, time.Time{}
+	case q.remaining <= 0:
+		return -1, q.reset
+	}
+	return q.remaining, time.Time{}
+}
+
+// wait blocks until a request may be sent, or returns a rate-limit error
+// without contacting GitHub if the quota will not free up soon
+func (t *rateTracker) wait(ctx context.Context, key quotaKey) error {
+	delay, reset, exhausted := t.reserve(key, time.Now())
+	if exhausted && delay > maxQuotaWait {
+		return &Error{
+			StatusCode:  http.StatusTooManyRequests,
+			Message:     "quota exhausted",
+			RateLimited: true,
+			Reset:       reset,
+		}
+	}
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		if exhausted {
+			return t.wait(ctx, key)
+		}
+		return nil
+	}
+}
+
+// update counts a response and records the quota reported in its
+// X-RateLimit-* headers
+func (t *rateTracker) update(key quotaKey, header http.Header) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	q := t.quota(key)
+	q.requests++
+
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, _ := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	used, _ := strconv.Atoi(header.Get("X-RateLimit-Used"))
+	epoch, _ := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+
+	q.limit = limit
+	q.remaining = remaining
+	q.used = used
+	q.reset = time.Unix(epoch, 0)
+}
+
+// block stops requests for key until apiErr.Reset, filling in a default
+// backoff when GitHub did not say how long to wait
+func (t *rateTracker) block(key quotaKey, apiErr *Error) {
+	if apiErr.Reset.IsZero() {
+		apiErr.Reset = time.Now().Add(secondaryLimitBackoff)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	q := t.quota(key)
+	if apiErr.Reset.After(q.blockedUntil) {
+		q.blockedUntil = apiErr.Reset
+	}
+}
+
+// status reports every known quota of token
+func (t *rateTracker) status(token string) []models.RateLimitStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	var statuses []models.RateLimitStatus
+	for key, q := range t.quotas {
+		if key.token != token {
+			continue
+		}
+		status := models.RateLimitStatus{
+			Token:     maskToken(token),
+			Resource:  key.resource,
+			Limit:     q.limit,
+			Remaining: q.remaining,
+			Used:      q.used,
+			ResetAt:   q.reset,
+			Requests:  q.requests,
+		}
+		if now.Before(q.blockedUntil) {
+			blockedUntil := q.blockedUntil
+			status.BlockedUntil = &blockedUntil
+		}
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Resource < statuses[j].Resource
+	})
+	return statuses
+}
+
+// quota returns the entry for key, creating it if needed (caller holds mu)
+func (t *rateTracker) quota(key quotaKey) *quota {
+	q := t.quotas[key]
+	if q == nil {
+		q = &quota{}
+		t.quotas[key] = q
+	}
+	return q
+}
+
+// maskToken hides all but the ends of a token for display
+func maskToken(token string) string {
+	if token == "" {
+		return "anonymous"
+	}
+	if len(token) <= 8 {
+		return "****"
+	}
+	return token[:4] + "…" + token[len(token)-4:]
+}
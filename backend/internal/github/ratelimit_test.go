@@ -0,0 +1,130 @@
+// Package github provides tests for rate-limit tracking
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func quotaHeaders(w http.ResponseWriter, limit, remaining int, reset time.Time) {
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	w.Header().Set("X-RateLimit-Used", strconv.Itoa(limit-remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+}
+
+func TestRateLimitTracksQuota(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	client := newTestClient(t, "token-abcdefgh", func(w http.ResponseWriter, r *http.Request) {
+		quotaHeaders(w, 5000, 4321, reset)
+		w.Write([]byte(`{}`))
+	})
+
+	if _, err := client.Get(context.Background(), "/users/octocat", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	status := client.RateLimit()
+	if len(status) != 1 {
+		t.Fatalf("Expected one tracked quota, got %+v", status)
+	}
+	if status[0].Resource != "core" || status[0].Limit != 5000 || status[0].Remaining != 4321 {
+		t.Errorf("Unexpected quota %+v", status[0])
+	}
+	if status[0].Token != "toke…efgh" {
+		t.Errorf("Token should be masked, got %q", status[0].Token)
+	}
+	if len(client.WithToken("other").RateLimit()) != 0 {
+		t.Error("Quotas should be tracked per token")
+	}
+}
+
+func TestExhaustedQuotaFailsFast(t *testing.T) {
+	var calls atomic.Int32
+	reset := time.Now().Add(time.Hour)
+	client := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		quotaHeaders(w, 60, 0, reset)
+		w.Write([]byte(`{}`))
+	})
+
+	client.Get(context.Background(), "/users/a", nil)
+	_, err := client.Get(context.Background(), "/users/b", nil)
+
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Expected ErrRateLimited, got %v", err)
+	}
+	if StatusCode(err) != http.StatusTooManyRequests {
+		t.Errorf("Expected a 429-style error, got %d", StatusCode(err))
+	}
+	if got := RateLimitReset(err); got.Unix() != reset.Unix() {
+		t.Errorf("Expected reset %v, got %v", reset, got)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Exhausted quota should not reach GitHub, got %d calls", calls.Load())
+	}
+}
+
+func TestSecondaryLimitBlocksFollowingRequests(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"You have exceeded a secondary rate limit"}`))
+	})
+
+	_, first := client.Get(context.Background(), "/users/a", nil)
+	_, second := client.Get(context.Background(), "/users/b", nil)
+
+	if !errors.Is(first, ErrRateLimited) || !errors.Is(second, ErrRateLimited) {
+		t.Fatalf("Expected both requests to be rate limited, got %v and %v", first, second)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Requests during Retry-After should not reach GitHub, got %d calls", calls.Load())
+	}
+	if status := client.RateLimit(); len(status) != 1 || status[0].BlockedUntil == nil {
+		t.Errorf("Blocked quota should be reported, got %+v", status)
+	}
+}
+
+func TestSearchQuotaTrackedSeparately(t *testing.T) {
+	reset := time.Now().Add(time.Minute)
+	client := newTestClient(t, "", func(w http.ResponseWriter, r *http.Request) {
+		quotaHeaders(w, 10, 0, reset)
+		w.Write([]byte(`{}`))
+	})
+
+	client.Get(context.Background(), "/search/users?q=a", nil)
+	if _, err := client.Get(context.Background(), "/users/a", nil); err != nil {
+		t.Errorf("Exhausting the search quota should not block core requests: %v", err)
+	}
+}
+
+func TestLowQuotaPacesRequests(t *testing.T) {
+	tracker := newRateTracker()
+	key := quotaKey{resource: "core"}
+	now := time.Now()
+	tracker.quotas[key] = &quota{limit: 100, remaining: 3, reset: now.Add(4 * time.Second)}
+
+	wait, _, exhausted := tracker.reserve(key, now)
+	if exhausted {
+		t.Fatal("Quota is low but not exhausted")
+	}
+	if wait != time.Second {
+		t.Errorf("Expected requests spread one second apart, got %v", wait)
+	}
+	if tracker.quotas[key].remaining != 2 {
+		t.Errorf("Reserved request should be counted, remaining=%d", tracker.quotas[key].remaining)
+	}
+
+	tracker.quotas[key].remaining = 90
+	if wait, _, _ := tracker.reserve(key, now); wait != 0 {
+		t.Errorf("Healthy quota should not be paced, got %v", wait)
+	}
+}
@@ -0,0 +1,169 @@
+// Package handlers provides developer activity HTTP handlers
+package handlers
+
+import (
+	"context"
+	"encoding/xml"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github-api/backend/internal/models"
+)
+
+// UsersHandler routes GET /api/user/{username}/activity and /network to the
+// activity report and collaboration graph, and every other /api/user/ path
+// to the extended profile
+func (s *Server) UsersHandler(w http.ResponseWriter, r *http.Request) {
+	username, sub, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/user/"), "/"), "/")
+
+	switch sub {
+	case "activity":
+		s.userActivityHandler(w, r, strings.TrimSpace(username))
+	case "network":
+		s.userNetworkHandler(w, r, strings.TrimSpace(username))
+	default:
+		s.GetExtendedUserHandler(w, r)
+	}
+}
+
+// userActivityHandler handles GET /api/user/{username}/activity[?tz=Area/City]
+func (s *Server) userActivityHandler(w http.ResponseWriter, r *http.Request, username string) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, models.APIResponse{Error: true, Message: "Method not allowed"})
+		return
+	}
+	if username == "" {
+		writeJSON(w, http.StatusBadRequest, models.APIResponse{Error: true, Message: "Username cannot be empty"})
+		return
+	}
+
+	loc, err := requestLocation(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, models.APIResponse{Error: true, Message: err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+
+	useCache := r.URL.Query().Get("no_cache") != "true"
+	result, err := s.service.GetUserActivity(ctx, username, useCache, loc)
+	if err != nil {
+		writeServiceError(w, &models.APIResponse{Error: true, Message: err.Error()}, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.APIResponse{Error: false, Data: result})
+}
+
+// userNetworkHandler handles GET /api/user/{username}/network[?format=graphml]
+func (s *Server) userNetworkHandler(w http.ResponseWriter, r *http.Request, username string) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, models.APIResponse{Error: true, Message: "Method not allowed"})
+		return
+	}
+	if username == "" {
+		writeJSON(w, http.StatusBadRequest, models.APIResponse{Error: true, Message: "Username cannot be empty"})
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "graphml" {
+		writeJSON(w, http.StatusBadRequest, models.APIResponse{Error: true, Message: "format must be json or graphml"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+
+	useCache := r.URL.Query().Get("no_cache") != "true"
+	result, err := s.service.GetUserNetwork(ctx, username, useCache)
+	if err != nil {
+		writeServiceError(w, &models.APIResponse{Error: true, Message: err.Error()}, err)
+		return
+	}
+
+	if format == "graphml" {
+		writeGraphML(w, result)
+		return
+	}
+	writeJSON(w, http.StatusOK, models.APIResponse{Error: false, Data: result})
+}
+
+// graphML is the root of a GraphML document
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+// graphMLKey declares a data attribute of nodes or edges
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// writeGraphML writes a collaboration graph as a GraphML document
+func writeGraphML(w http.ResponseWriter, network models.UserNetwork) {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "type", For: "node", Name: "type", Type: "string"},
+			{ID: "label", For: "node", Name: "label", Type: "string"},
+			{ID: "node_weight", For: "node", Name: "weight", Type: "int"},
+			{ID: "kind", For: "edge", Name: "kind", Type: "string"},
+			{ID: "weight", For: "edge", Name: "weight", Type: "int"},
+		},
+		Graph: graphMLGraph{ID: network.Username, EdgeDefault: "directed"},
+	}
+	for _, node := range network.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: node.ID, Data: []graphMLData{
+			{Key: "type", Value: node.Type},
+			{Key: "label", Value: node.Label},
+			{Key: "node_weight", Value: strconv.Itoa(node.Weight)},
+		}})
+	}
+	for _, edge := range network.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{Source: edge.Source, Target: edge.Target, Data: []graphMLData{
+			{Key: "kind", Value: edge.Kind},
+			{Key: "weight", Value: strconv.Itoa(edge.Weight)},
+		}})
+	}
+
+	w.Header().Set("Content-Type", "application/graphml+xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		log.Printf("⚠️ [Network] Failed to write GraphML for %s: %v", network.Username, err)
+	}
+}
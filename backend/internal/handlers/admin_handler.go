@@ -5,14 +5,15 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"strings"
 	"sync"
 	"time"
 
+	"github-api/backend/internal/github"
 	"github-api/backend/internal/models"
 )
 
@@ -21,12 +22,13 @@ var AdminUsernames = []string{"anantacoder", "AnantaCoder"}
 
 // AdminHandler handles admin-only operations
 type AdminHandler struct {
-	db *sql.DB
+	db     *sql.DB
+	github *github.Client
 }
 
 // NewAdminHandler creates a new admin handler
-func NewAdminHandler(db *sql.DB) *AdminHandler {
-	return &AdminHandler{db: db}
+func NewAdminHandler(db *sql.DB, client *github.Client) *AdminHandler {
+	return &AdminHandler{db: db, github: client}
 }
 
 // UpdateAllPrivateDataResponse is the response for the update endpoint
@@ -148,6 +150,7 @@ func (h *AdminHandler) GetUpdateStatus(w http.ResponseWriter, r *http.Request) {
 		"last_update":    lastUpdateStr,
 		"is_admin":       true,
 		"admin_username": user.Username,
+		"github_tokens":  h.github.RateLimit(),
 	}
 
 	writeJSON(w, http.StatusOK, response)
@@ -228,7 +231,7 @@ func (h *AdminHandler) updateAllUsersPrivateData(ctx context.Context) (*UpdateAl
 					result.FailedUsers = append(result.FailedUsers, fmt.Sprintf("%d", u.ID))
 				}
 				// If the failure is due to bad credentials (401), disable private access for the user
-				if strings.Contains(err.Error(), "API error 401") || strings.Contains(strings.ToLower(err.Error()), "bad credentials") {
+				if errors.Is(err, github.ErrUnauthorized) {
 					// Attempt to mark user has_private_access = false
 					if _, dbErr := h.db.ExecContext(ctx, "UPDATE users SET has_private_access = FALSE WHERE id = $1", u.ID); dbErr != nil {
 						log.Printf("[ADMIN] Error disabling private access for user %d (%s): %v", u.ID, u.Username, dbErr)
@@ -260,39 +263,41 @@ func (h *AdminHandler) updateAllUsersPrivateData(ctx context.Context) (*UpdateAl
 }
 
 func (h *AdminHandler) processUser(ctx context.Context, user userRow) error {
+	client := h.github.WithToken(user.Token)
+
 	// Fetch user data from GitHub
-	userData, err := fetchGitHubAPIAdmin[GitHubUserData](ctx, "/user", user.Token)
+	userData, err := github.GetJSON[GitHubUserData](ctx, client, "/user")
 	if err != nil {
 		return fmt.Errorf("fetch /user: %w", err)
 	}
 
 	// Fetch emails
-	emails, _ := fetchGitHubAPIAdmin[[]GitHubEmailData](ctx, "/user/emails", user.Token)
+	emails, _ := github.GetJSON[[]GitHubEmailData](ctx, client, "/user/emails")
 	if emails == nil {
 		emails = &[]GitHubEmailData{}
 	}
 
 	// Fetch orgs
-	orgs, _ := fetchGitHubAPIAdmin[[]GitHubOrgData](ctx, "/user/orgs", user.Token)
+	orgs, _ := github.GetJSON[[]GitHubOrgData](ctx, client, "/user/orgs")
 	if orgs == nil {
 		orgs = &[]GitHubOrgData{}
 	}
 
 	// Fetch private repos
-	repos, _ := fetchGitHubAPIAdmin[[]GitHubRepoData](ctx, "/user/repos?visibility=private&per_page=100", user.Token)
+	repos, _ := github.GetJSON[[]GitHubRepoData](ctx, client, "/user/repos?visibility=private&per_page=100")
 	if repos == nil {
 		repos = &[]GitHubRepoData{}
 	}
 
 	// Fetch SSH keys
-	sshKeys, _ := fetchGitHubAPIAdmin[[]map[string]interface{}](ctx, "/user/keys", user.Token)
+	sshKeys, _ := github.GetJSON[[]map[string]interface{}](ctx, client, "/user/keys")
 	sshKeysCount := 0
 	if sshKeys != nil {
 		sshKeysCount = len(*sshKeys)
 	}
 
 	// Fetch GPG keys
-	gpgKeys, _ := fetchGitHubAPIAdmin[[]map[string]interface{}](ctx, "/user/gpg_keys", user.Token)
+	gpgKeys, _ := github.GetJSON[[]map[string]interface{}](ctx, client, "/user/gpg_keys")
 	gpgKeysCount := 0
 	if gpgKeys != nil {
 		gpgKeysCount = len(*gpgKeys)
@@ -431,43 +436,6 @@ type GitHubRepoData struct {
 	Private bool   `json:"private"`
 }
 
-func fetchGitHubAPIAdmin[T any](ctx context.Context, path, token string) (*T, error) {
-	url := "https://api.github.com" + path
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("User-Agent", "DevScope-Admin")
-	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	var result T
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, err
-	}
-
-	return &result, nil
-}
-
 // IsAdminUser checks if a user is an admin (exported for use in other packages)
 func IsAdminUser(user *models.User) bool {
 	if user == nil {
@@ -3,18 +3,45 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
+	"github-api/backend/internal/breaker"
 	"github-api/backend/internal/models"
+	"github-api/backend/internal/retry"
 )
 
 const NVIDIA_API_BASE = "https://integrate.api.nvidia.com/v1"
 
+// nvidiaRetryPolicy retries transient NVIDIA failures; each handler still
+// bounds the whole call, retries included, with its own timeout
+var nvidiaRetryPolicy = retry.Policy{
+	MaxAttempts: 3,
+	MaxElapsed:  30 * time.Second,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    4 * time.Second,
+}
+
+// The AI provider is treated as down after nvidiaBreakerThreshold consecutive
+// failed calls and is not called again for nvidiaBreakerCooldown. A call it
+// hasn't answered within nvidiaTimeout counts as failed; handlers giving up
+// sooner on their own deadline don't count.
+const (
+	nvidiaBreakerThreshold = 5
+	nvidiaBreakerCooldown  = 30 * time.Second
+	nvidiaTimeout          = 90 * time.Second
+)
+
+// aiUnavailableMessage is returned while the AI provider's breaker is open
+const aiUnavailableMessage = "AI service is temporarily unavailable, please try again shortly"
+
 // AI_MODEL_NAME is the single source of truth for the AI model used across all handlers
 const AI_MODEL_NAME = "qwen/qwen3-coder-480b-a35b-instruct"
 
@@ -113,20 +140,21 @@ func (s *Server) AIComparisonHandler(w http.ResponseWriter, r *http.Request) {
 		MaxTokens:   600,
 	}
 
-	jsonData, _ := json.Marshal(nvidiaReq)
-	httpReq, err := http.NewRequest("POST", NVIDIA_API_BASE+"/chat/completions", bytes.NewBuffer(jsonData))
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	httpReq, err := newNVIDIARequest(ctx, apiKey, nvidiaReq)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, AIComparisonResponse{Error: true, Message: "Failed to create AI request"})
 		return
 	}
 
-	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(httpReq)
+	resp, err := s.aiClient.Do(httpReq)
 	if err != nil {
 		log.Printf("❌ [AI] Request failed: %v", err)
+		if errors.Is(err, breaker.ErrOpen) {
+			writeUnavailable(w, err, AIComparisonResponse{Error: true, Message: aiUnavailableMessage})
+			return
+		}
 		writeJSON(w, http.StatusInternalServerError, AIComparisonResponse{Error: true, Message: "Failed to connect to NVIDIA API"})
 		return
 	}
@@ -155,6 +183,22 @@ func (s *Server) AIComparisonHandler(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, AIComparisonResponse{Error: false, Comparison: comparison})
 }
 
+// newNVIDIARequest builds a chat completion request. Completions have no side
+// effects, so the request is marked safe to retry on transient failures.
+func newNVIDIARequest(ctx context.Context, apiKey string, payload NVIDIARequest) (*http.Request, error) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(retry.WithIdempotent(ctx), http.MethodPost, NVIDIA_API_BASE+"/chat/completions", bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
 // buildComparisonPrompt creates a concise prompt for AI comparison
 func buildComparisonPrompt(users []models.GitHubUser) string {
 	prompt := "Compare these GitHub developers briefly:\n\n"
@@ -213,6 +257,9 @@ type AIAnalyzeRequest struct {
 	PublicRepos int    `json:"public_repos,omitempty"`
 }
 
+// repoHealthTimeout bounds the health check gathered for a repo analysis
+const repoHealthTimeout = 15 * time.Second
+
 // AIAnalyzeResponse represents the analysis response
 type AIAnalyzeResponse struct {
 	Error    bool   `json:"error"`
@@ -266,8 +313,21 @@ func (s *Server) AIAnalyzeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Ground repo analyses in the health checklist; analysis goes ahead without it
+	var health *models.RepoHealth
+	if req.Type == "repo" && req.RepoOwner != "" && req.RepoName != "" {
+		healthCtx, cancel := context.WithTimeout(r.Context(), repoHealthTimeout)
+		result, err := s.service.GetRepoHealth(healthCtx, req.RepoOwner, req.RepoName, true)
+		cancel()
+		if err != nil {
+			log.Printf("⚠️ [AI] Health check unavailable for %s/%s: %v", req.RepoOwner, req.RepoName, err)
+		} else {
+			health = &result
+		}
+	}
+
 	// Build analysis prompt based on type
-	prompt := buildAnalyzePrompt(req)
+	prompt := buildAnalyzePrompt(req, health)
 	log.Printf("🔄 [AI] Analyzing %s: %s...", req.Type, req.Username+req.RepoName)
 
 	nvidiaReq := NVIDIARequest{
@@ -280,20 +340,21 @@ func (s *Server) AIAnalyzeHandler(w http.ResponseWriter, r *http.Request) {
 		MaxTokens:   150,
 	}
 
-	jsonData, _ := json.Marshal(nvidiaReq)
-	httpReq, err := http.NewRequest("POST", NVIDIA_API_BASE+"/chat/completions", bytes.NewBuffer(jsonData))
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	httpReq, err := newNVIDIARequest(ctx, apiKey, nvidiaReq)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, AIAnalyzeResponse{Error: true, Message: "Failed to create AI request"})
 		return
 	}
 
-	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(httpReq)
+	resp, err := s.aiClient.Do(httpReq)
 	if err != nil {
 		log.Printf("❌ [AI] Request failed: %v", err)
+		if errors.Is(err, breaker.ErrOpen) {
+			writeUnavailable(w, err, AIAnalyzeResponse{Error: true, Message: aiUnavailableMessage})
+			return
+		}
 		writeJSON(w, http.StatusInternalServerError, AIAnalyzeResponse{Error: true, Message: "Failed to connect to NVIDIA API"})
 		return
 	}
@@ -329,21 +390,22 @@ func (s *Server) AIAnalyzeHandler(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, AIAnalyzeResponse{Error: false, Analysis: analysis})
 }
 
-// buildAnalyzePrompt creates a prompt for single entity analysis
-func buildAnalyzePrompt(req AIAnalyzeRequest) string {
+// buildAnalyzePrompt creates a prompt for single entity analysis, including
+// the repository health checklist when one is available
+func buildAnalyzePrompt(req AIAnalyzeRequest, health *models.RepoHealth) string {
 	if req.Type == "repo" {
 		return fmt.Sprintf(`Analyze this GitHub repository briefly:
 
 **%s/%s**
 • Description: %s
 • Language: %s
-• Stars: %d | Forks: %d
+• Stars: %d | Forks: %d%s
 
 Provide:
 1. **Summary**: What this project is (1 sentence)
 2. **Strengths**: 2 bullet points
 3. **Suggestions**: 1-2 improvements`,
-			req.RepoOwner, req.RepoName, req.Description, req.Language, req.Stars, req.Forks)
+			req.RepoOwner, req.RepoName, req.Description, req.Language, req.Stars, req.Forks, healthSummary(health))
 	}
 
 	// User analysis
@@ -359,3 +421,19 @@ Provide:
 3. **Growth Tip**: 1 actionable suggestion`,
 		req.Username, req.PublicRepos, req.Followers)
 }
+
+// healthSummary lists a repository's health score and failed checks for a prompt
+func healthSummary(health *models.RepoHealth) string {
+	if health == nil {
+		return ""
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "\n• Health score: %d/%d", health.Score, health.MaxScore)
+	for _, check := range health.Checks {
+		if !check.Passed && !check.Unscored {
+			fmt.Fprintf(&summary, "\n• Needs work: %s (%s)", check.Label, check.Detail)
+		}
+	}
+	return summary.String()
+}
@@ -7,11 +7,14 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"time"
 
 	"github-api/backend/internal/auth"
+	"github-api/backend/internal/cache"
+	"github-api/backend/internal/github"
 	"github-api/backend/internal/models"
 	"github-api/backend/internal/repository"
 )
@@ -25,8 +28,19 @@ type AuthHandler struct {
 	}
 	frontendURL string
 	stateTTL    map[string]time.Time
+
+	// Notifications per user ID, revalidated with Last-Modified once expired
+	notifications *cache.Cache[int, []GitHubNotification]
 }
 
+const (
+	// notificationsTTL matches GitHub's default X-Poll-Interval for notifications
+	notificationsTTL = time.Minute
+
+	// notificationsRetention is how long expired notifications are kept for revalidation
+	notificationsRetention = time.Hour
+)
+
 // NewAuthHandler creates a new auth handler
 func NewAuthHandler(authService *auth.AuthService, userRepo *repository.UserRepository, frontendURL string, rankingService interface {
 	UpdateUserRanking(ctx context.Context, username string) error
@@ -37,7 +51,9 @@ func NewAuthHandler(authService *auth.AuthService, userRepo *repository.UserRepo
 		rankingService: rankingService,
 		frontendURL:    frontendURL,
 		stateTTL:       make(map[string]time.Time),
+		notifications:  cache.New[int, []GitHubNotification](1000, notificationsTTL),
 	}
+	handler.notifications.SetStaleTTL(notificationsRetention)
 
 	// Clean up expired states periodically
 	go handler.cleanupExpiredStates()
@@ -263,6 +279,61 @@ func (h *AuthHandler) MeHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// PreferencesHandler handles POST /api/auth/me/preferences, saving the time
+// zone used for the user's streaks when a request does not pass tz
+func (h *AuthHandler) PreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, models.AuthResponse{
+			Error:   true,
+			Message: "Method not allowed",
+		})
+		return
+	}
+
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok {
+		writeJSON(w, http.StatusUnauthorized, models.AuthResponse{
+			Error:   true,
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	var req struct {
+		Timezone string `json:"timezone"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, models.AuthResponse{
+			Error:   true,
+			Message: "Invalid request body",
+		})
+		return
+	}
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		writeJSON(w, http.StatusBadRequest, models.AuthResponse{
+			Error:   true,
+			Message: fmt.Sprintf("Unknown time zone %q", req.Timezone),
+		})
+		return
+	}
+
+	if err := h.userRepo.UpdateTimezone(r.Context(), user.ID, req.Timezone); err != nil {
+		log.Printf("❌ [Auth] Failed to save timezone for user %d: %v", user.ID, err)
+		writeJSON(w, http.StatusInternalServerError, models.AuthResponse{
+			Error:   true,
+			Message: "Failed to save preferences",
+		})
+		return
+	}
+
+	updated := *user
+	updated.Timezone = req.Timezone
+	writeJSON(w, http.StatusOK, models.AuthResponse{
+		Error: false,
+		User:  &updated,
+	})
+}
+
 // MeFullHandler returns current user's full GitHub data including private repos
 func (h *AuthHandler) MeFullHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -423,7 +494,7 @@ func (h *AuthHandler) NotificationsHandler(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Fetch notifications from GitHub API
-	notifications, err := h.fetchGitHubNotifications(userWithToken.AccessToken)
+	notifications, err := h.fetchGitHubNotifications(ctx, user.ID, userWithToken.AccessToken)
 	if err != nil {
 		log.Printf("❌ [Notifications] Failed to fetch for user %s: %v", user.Username, err)
 		writeJSON(w, http.StatusInternalServerError, map[string]interface{}{
@@ -465,33 +536,33 @@ type GitHubNotification struct {
 	} `json:"repository"`
 }
 
-// fetchGitHubNotifications fetches notifications from GitHub API
-func (h *AuthHandler) fetchGitHubNotifications(accessToken string) ([]GitHubNotification, error) {
-	req, err := http.NewRequest("GET", "https://api.github.com/notifications?per_page=50", nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+accessToken)
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("User-Agent", "DevScope-App")
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
+// fetchGitHubNotifications fetches notifications from GitHub API, serving
+// them from cache for notificationsTTL and then revalidating the cached copy
+// with a conditional request
+func (h *AuthHandler) fetchGitHubNotifications(ctx context.Context, userID int, accessToken string) ([]GitHubNotification, error) {
+	if notifications, found := h.notifications.Get(userID); found {
+		return notifications, nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	var validators github.Validators
+	if etag, lastModified, ok := h.notifications.Revalidate(userID); ok {
+		validators = github.Validators{ETag: etag, LastModified: lastModified}
 	}
 
 	var notifications []GitHubNotification
-	if err := json.NewDecoder(resp.Body).Decode(&notifications); err != nil {
+	resp, notModified, err := h.authService.GitHub(accessToken).GetConditional(ctx, "/notifications?per_page=50", validators, &notifications)
+	if err != nil {
 		return nil, err
 	}
+	if notModified {
+		if cached, ok := h.notifications.Touch(userID); ok {
+			return cached, nil
+		}
+		return h.fetchGitHubNotifications(ctx, userID, accessToken)
+	}
 
+	fresh := github.ValidatorsFrom(resp)
+	h.notifications.SetWithValidators(userID, notifications, notificationsTTL, fresh.ETag, fresh.LastModified)
 	return notifications, nil
 }
 
@@ -538,38 +609,22 @@ func (h *AuthHandler) MarkNotificationReadHandler(w http.ResponseWriter, r *http
 		return
 	}
 
-	// Mark notification as read on GitHub
-	req, err := http.NewRequest("PATCH", fmt.Sprintf("https://api.github.com/notifications/threads/%s", notificationID), nil)
-	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]interface{}{
-			"error":   true,
-			"message": "Failed to create request",
-		})
-		return
-	}
-
-	req.Header.Set("Authorization", "Bearer "+userWithToken.AccessToken)
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("User-Agent", "DevScope-App")
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
+	// Mark notification as read on GitHub (responds 205 Reset Content)
+	threadPath := "/notifications/threads/" + url.PathEscape(notificationID)
+	if _, err := h.authService.GitHub(userWithToken.AccessToken).Send(ctx, http.MethodPatch, threadPath, nil, nil); err != nil {
+		message := "Failed to mark notification as read"
+		if github.StatusCode(err) != 0 {
+			message = err.Error()
+		}
 		writeJSON(w, http.StatusInternalServerError, map[string]interface{}{
 			"error":   true,
-			"message": "Failed to mark notification as read",
+			"message": message,
 		})
 		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusResetContent && resp.StatusCode != http.StatusOK {
-		writeJSON(w, http.StatusInternalServerError, map[string]interface{}{
-			"error":   true,
-			"message": fmt.Sprintf("GitHub API returned status %d", resp.StatusCode),
-		})
-		return
-	}
+	// The cached list still shows the thread as unread
+	h.notifications.Delete(user.ID)
 
 	writeJSON(w, http.StatusOK, map[string]interface{}{
 		"error":   false,
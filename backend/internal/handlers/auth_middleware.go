@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github-api/backend/internal/auth"
+	"github-api/backend/internal/github"
 	"github-api/backend/internal/models"
 )
 
@@ -42,9 +43,7 @@ func (m *AuthMiddleware) RequireAuth(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		// Add user to context
-		ctx := context.WithValue(r.Context(), "user", user)
-		next.ServeHTTP(w, r.WithContext(ctx))
+		next.ServeHTTP(w, r.WithContext(withSessionUser(r.Context(), user)))
 	}
 }
 
@@ -57,9 +56,7 @@ func (m *AuthMiddleware) OptionalAuth(next http.HandlerFunc) http.HandlerFunc {
 			// Validate session
 			user, err := m.authService.ValidateSession(r.Context(), cookie.Value)
 			if err == nil && user != nil {
-				// Add user to context
-				ctx := context.WithValue(r.Context(), "user", user)
-				r = r.WithContext(ctx)
+				r = r.WithContext(withSessionUser(r.Context(), user))
 			}
 		}
 
@@ -67,6 +64,13 @@ func (m *AuthMiddleware) OptionalAuth(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// withSessionUser adds the authenticated user to ctx and makes GitHub calls
+// made on their behalf through the shared client use their own token
+func withSessionUser(ctx context.Context, user *models.UserWithToken) context.Context {
+	ctx = context.WithValue(ctx, "user", &user.User)
+	return github.ContextWithUserToken(ctx, user.AccessToken, user.Username)
+}
+
 // GetUserFromContext retrieves user from request context
 func GetUserFromContext(ctx context.Context) (*models.User, bool) {
 	user, ok := ctx.Value("user").(*models.User)
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github-api/backend/internal/cache"
+	"github-api/backend/internal/models"
+)
+
+// cacheInvalidateRequest selects entries to invalidate; an empty Cache targets every cache
+type cacheInvalidateRequest struct {
+	Cache  string `json:"cache"`
+	Key    string `json:"key"`
+	Prefix string `json:"prefix"`
+}
+
+// cacheConfigRequest changes cache settings at runtime; zero values are left unchanged
+type cacheConfigRequest struct {
+	Cache      string `json:"cache"`
+	TTLSeconds int    `json:"ttl_seconds"`
+	MaxSize    int    `json:"max_size"`
+}
+
+// requireAdmin writes an error response and returns nil unless the request comes from an admin
+func requireAdmin(w http.ResponseWriter, r *http.Request) *models.User {
+	user, ok := GetUserFromContext(r.Context())
+	if !ok || user == nil {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+		return nil
+	}
+	if !IsAdminUser(user) {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "Forbidden: Admin access required"})
+		return nil
+	}
+	return user
+}
+
+// selectCaches resolves a cache name to the caches it refers to (all caches when empty)
+func (s *Server) selectCaches(w http.ResponseWriter, name string) (map[string]cache.Manager, bool) {
+	caches := s.service.Caches()
+	if name == "" {
+		return caches, true
+	}
+	c, ok := caches[name]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Unknown cache: " + name})
+		return nil, false
+	}
+	return map[string]cache.Manager{name: c}, true
+}
+
+// CacheClearHandler handles POST /api/cache/clear (admin only)
+func (s *Server) CacheClearHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "Method not allowed"})
+		return
+	}
+	user := requireAdmin(w, r)
+	if user == nil {
+		return
+	}
+
+	s.service.ClearCache()
+	log.Printf("[ADMIN] User %s cleared all caches", user.Username)
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Cache cleared successfully"})
+}
+
+// AdminCacheKeysHandler handles GET /api/admin/cache/keys?cache={name}
+func (s *Server) AdminCacheKeysHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "Method not allowed"})
+		return
+	}
+	if requireAdmin(w, r) == nil {
+		return
+	}
+
+	caches, ok := s.selectCaches(w, r.URL.Query().Get("cache"))
+	if !ok {
+		return
+	}
+
+	response := make(map[string]interface{}, len(caches))
+	for name, c := range caches {
+		response[name] = map[string]interface{}{
+			"stats": c.Stats(),
+			"keys":  c.Keys(),
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// AdminCacheEntryHandler handles GET /api/admin/cache/entry?cache={name}&key={key}
+func (s *Server) AdminCacheEntryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "Method not allowed"})
+		return
+	}
+	if requireAdmin(w, r) == nil {
+		return
+	}
+
+	name := r.URL.Query().Get("cache")
+	key := r.URL.Query().Get("key")
+	if name == "" || key == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "cache and key are required"})
+		return
+	}
+
+	caches, ok := s.selectCaches(w, name)
+	if !ok {
+		return
+	}
+
+	entry, found := caches[name].Entry(key)
+	if !found {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Entry not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, entry)
+}
+
+// AdminCacheInvalidateHandler handles POST /api/admin/cache/invalidate
+func (s *Server) AdminCacheInvalidateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "Method not allowed"})
+		return
+	}
+	user := requireAdmin(w, r)
+	if user == nil {
+		return
+	}
+
+	var req cacheInvalidateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+	if (req.Key == "") == (req.Prefix == "") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Exactly one of key or prefix is required"})
+		return
+	}
+
+	caches, ok := s.selectCaches(w, req.Cache)
+	if !ok {
+		return
+	}
+
+	removed := make(map[string]int, len(caches))
+	for name, c := range caches {
+		if req.Key != "" {
+			if c.Delete(req.Key) {
+				removed[name] = 1
+			} else {
+				removed[name] = 0
+			}
+			continue
+		}
+		removed[name] = c.DeletePrefix(req.Prefix)
+	}
+
+	log.Printf("[ADMIN] User %s invalidated cache entries (cache=%q key=%q prefix=%q): %v",
+		user.Username, req.Cache, req.Key, req.Prefix, removed)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Cache entries invalidated",
+		"removed": removed,
+	})
+}
+
+// AdminCacheConfigHandler handles POST /api/admin/cache/config
+func (s *Server) AdminCacheConfigHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "Method not allowed"})
+		return
+	}
+	user := requireAdmin(w, r)
+	if user == nil {
+		return
+	}
+
+	var req cacheConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		return
+	}
+	if req.TTLSeconds < 0 || req.MaxSize < 0 || (req.TTLSeconds == 0 && req.MaxSize == 0) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Provide a positive ttl_seconds and/or max_size"})
+		return
+	}
+
+	caches, ok := s.selectCaches(w, req.Cache)
+	if !ok {
+		return
+	}
+
+	stats := make(map[string]models.CacheStats, len(caches))
+	for name, c := range caches {
+		c.Configure(time.Duration(req.TTLSeconds)*time.Second, req.MaxSize)
+		stats[name] = c.Stats()
+	}
+
+	log.Printf("[ADMIN] User %s reconfigured cache %q: ttl=%ds max_size=%d",
+		user.Username, req.Cache, req.TTLSeconds, req.MaxSize)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Cache configuration updated",
+		"caches":  stats,
+	})
+}
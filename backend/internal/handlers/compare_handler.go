@@ -0,0 +1,57 @@
+// Package handlers provides user comparison HTTP handlers
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github-api/backend/internal/models"
+)
+
+// CompareHandler handles POST /api/compare, relating two or more users
+// through their followers, following, contributions, stars and languages
+func (s *Server) CompareHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, models.APIResponse{Error: true, Message: "Method not allowed"})
+		return
+	}
+
+	var req models.BatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, models.APIResponse{Error: true, Message: "Invalid JSON body"})
+		return
+	}
+
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, username := range req.Usernames {
+		username = strings.TrimSpace(username)
+		if username != "" && !seen[strings.ToLower(username)] {
+			seen[strings.ToLower(username)] = true
+			usernames = append(usernames, username)
+		}
+	}
+
+	if len(usernames) < 2 || len(usernames) > s.config.MaxBatchSize {
+		writeJSON(w, http.StatusBadRequest, models.APIResponse{
+			Error:   true,
+			Message: fmt.Sprintf("Compare between 2 and %d distinct usernames", s.config.MaxBatchSize),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+
+	useCache := r.URL.Query().Get("no_cache") != "true"
+	result, err := s.service.CompareUsers(ctx, usernames, useCache)
+	if err != nil {
+		writeServiceError(w, &models.APIResponse{Error: true, Message: err.Error()}, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.APIResponse{Error: false, Data: result})
+}
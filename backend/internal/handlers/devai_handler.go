@@ -2,24 +2,28 @@
 package handlers
 
 import (
-	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
+	"github-api/backend/internal/breaker"
+	"github-api/backend/internal/github"
 	"github-api/backend/internal/models"
+	"github-api/backend/internal/service"
 )
 
 // DevAIMention represents an @ mention in the chat
 type DevAIMention struct {
-	Type  string `json:"type"`  // "repo" or "user"
+	Type  string `json:"type"`  // "repo", "user", "file", "pr" or "maintainers"
 	Value string `json:"value"` // e.g., "owner/repo" or "username"
 }
 
@@ -141,7 +145,7 @@ func (s *Server) DevAIChatHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Build context from mentions
-	mentionContext := buildMentionContext(req.Mentions)
+	mentionContext := buildMentionContext(r.Context(), s.service, req.Mentions)
 
 	// Build the prompt with user context
 	prompt := buildDevAIPrompt(user, req.Message, mentionContext)
@@ -185,20 +189,21 @@ func (s *Server) DevAIChatHandler(w http.ResponseWriter, r *http.Request) {
 		MaxTokens:   800,
 	}
 
-	jsonData, _ := json.Marshal(nvidiaReq)
-	httpReq, err := http.NewRequest("POST", NVIDIA_API_BASE+"/chat/completions", bytes.NewBuffer(jsonData))
+	ctx, cancel := context.WithTimeout(r.Context(), 90*time.Second)
+	defer cancel()
+	httpReq, err := newNVIDIARequest(ctx, apiKey, nvidiaReq)
 	if err != nil {
 		writeJSON(w, http.StatusInternalServerError, DevAIChatResponse{Error: true, Message: "Failed to create AI request"})
 		return
 	}
 
-	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{Timeout: 90 * time.Second}
-	resp, err := client.Do(httpReq)
+	resp, err := s.aiClient.Do(httpReq)
 	if err != nil {
 		log.Printf("❌ [DevAI] Request failed: %v", err)
+		if errors.Is(err, breaker.ErrOpen) {
+			writeUnavailable(w, err, DevAIChatResponse{Error: true, Message: aiUnavailableMessage})
+			return
+		}
 		writeJSON(w, http.StatusInternalServerError, DevAIChatResponse{Error: true, Message: "Failed to connect to AI service"})
 		return
 	}
@@ -286,6 +291,7 @@ You are chatting with %s (@%s), who has %d public repositories and %d followers
 Your capabilities:
 - Answer questions about GitHub, Git, and software development
 - Provide information about repositories and users when they are mentioned with @repo or @user
+- Assess maintenance risk (bus factor, commit concentration, maintainer churn) from the figures included with @repo and @maintainers mentions
 - Help with coding questions, best practices, and debugging
 - Explain GitHub features and workflows
 
@@ -309,39 +315,118 @@ func buildDevAIPrompt(user *models.User, message string, mentionContext string)
 }
 
 // buildMentionContext creates context string from mentions by fetching real data from GitHub
-func buildMentionContext(mentions []DevAIMention) string {
+// as the signed-in user, so mentions of private repos and files they can access resolve
+func buildMentionContext(ctx context.Context, svc *service.GitHubService, mentions []DevAIMention) string {
 	if len(mentions) == 0 {
 		return ""
 	}
+	client := svc.GitHub()
 
 	var parts []string
-	client := &http.Client{Timeout: 15 * time.Second}
 
 	for _, m := range mentions {
 		switch m.Type {
 		case "repo":
-			// Fetch repository data from GitHub API
-			repoData := fetchGitHubRepoData(client, m.Value)
+			// Fetch repository data from GitHub API, with its maintenance risk
+			repoData := fetchGitHubRepoData(ctx, client, m.Value)
+			if risk := repoMaintenanceRisk(ctx, svc, m.Value); risk != "" {
+				repoData += "\n\n" + risk
+			}
 			parts = append(parts, repoData)
 		case "user":
 			// Fetch user data from GitHub API
-			userData := fetchGitHubUserData(client, m.Value)
+			userData := fetchGitHubUserData(ctx, client, m.Value)
 			parts = append(parts, userData)
 		case "file":
 			// Fetch file content from GitHub API
-			fileData := fetchGitHubFileContent(client, m.Value)
+			fileData := fetchGitHubFileContent(ctx, client, m.Value)
 			parts = append(parts, fileData)
 		case "pr":
 			// Fetch PR data from GitHub API
-			prData := fetchGitHubPRData(client, m.Value)
+			prData := fetchGitHubPRData(ctx, client, m.Value)
 			parts = append(parts, prData)
+		case "maintainers":
+			// Summarise contributor concentration for maintenance risk
+			parts = append(parts, fetchMaintenanceRisk(ctx, svc, m.Value))
 		}
 	}
 	return strings.Join(parts, "\n\n")
 }
 
+// fetchMaintenanceRisk summarises how dependent a repository is on few
+// contributors over the default window
+func fetchMaintenanceRisk(ctx context.Context, svc *service.GitHubService, fullName string) string {
+	risk, err := maintenanceRisk(ctx, svc, fullName)
+	switch {
+	case errors.Is(err, errInvalidRepoName):
+		return fmt.Sprintf("Maintainers: %s (invalid format - use owner/repo)", fullName)
+	case errors.Is(err, service.ErrRepoNotFound):
+		return fmt.Sprintf("Maintainers: %s (repository not found or private)", fullName)
+	case err != nil:
+		log.Printf("⚠️ [DevAI] Failed to fetch contributors of %s: %v", fullName, err)
+		return fmt.Sprintf("Maintainers: %s (unable to fetch)", fullName)
+	}
+	return risk
+}
+
+// repoMaintenanceRisk is the maintenance risk added to a repo mention, or
+// nothing when it is unavailable
+func repoMaintenanceRisk(ctx context.Context, svc *service.GitHubService, fullName string) string {
+	risk, err := maintenanceRisk(ctx, svc, fullName)
+	if err != nil && !errors.Is(err, errInvalidRepoName) && !errors.Is(err, service.ErrRepoNotFound) {
+		log.Printf("⚠️ [DevAI] Failed to fetch contributors of %s: %v", fullName, err)
+	}
+	return risk
+}
+
+// errInvalidRepoName reports a mention that is not of the form owner/repo
+var errInvalidRepoName = errors.New("invalid repository name")
+
+// maintenanceRisk fetches a repository's contributor concentration over the
+// default window and describes it for a prompt
+func maintenanceRisk(ctx context.Context, svc *service.GitHubService, fullName string) (string, error) {
+	owner, repo, ok := strings.Cut(fullName, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", errInvalidRepoName
+	}
+
+	report, err := svc.GetContributorConcentration(ctx, owner, repo, service.DefaultContributorWindow, true)
+	if err != nil {
+		return "", err
+	}
+	return maintenanceRiskSummary(report), nil
+}
+
+// maintenanceRiskSummary describes a contributor concentration report for a prompt
+func maintenanceRiskSummary(report models.ContributorConcentration) string {
+	var info strings.Builder
+	info.WriteString(fmt.Sprintf("=== Maintenance risk: %s ===\n", report.Repo))
+	info.WriteString(fmt.Sprintf("Window: last %d days, %d commits by %d contributors\n", report.WindowDays, report.Commits, len(report.Contributors)))
+	info.WriteString(fmt.Sprintf("Bus factor: %d (contributors authoring half the commits)\n", report.BusFactor))
+	info.WriteString(fmt.Sprintf("Gini coefficient of commit share: %.3f\n", report.Gini))
+	info.WriteString(fmt.Sprintf("Top contributor share: %.1f%%\n", report.TopShare))
+	for i, contributor := range report.Contributors {
+		if i == 5 {
+			break
+		}
+		info.WriteString(fmt.Sprintf("- @%s: %d commits (%.1f%%)\n", contributor.Login, contributor.Commits, contributor.Share))
+	}
+
+	if churn := report.Churn; churn != nil {
+		info.WriteString(fmt.Sprintf("Maintainer churn vs the previous %d days: %d of %d contributors left (rate %.2f), %d joined\n",
+			report.WindowDays, len(churn.Left), churn.Previous, churn.ChurnRate, len(churn.Joined)))
+	}
+	if report.AllTimeBusFactor > 0 {
+		info.WriteString(fmt.Sprintf("All-time bus factor: %d\n", report.AllTimeBusFactor))
+	}
+	if report.Truncated {
+		info.WriteString("Note: commit history was truncated; older commits are missing and churn is unknown\n")
+	}
+	return info.String()
+}
+
 // fetchGitHubPRData fetches Pull Request data from GitHub
-func fetchGitHubPRData(client *http.Client, prRef string) string {
+func fetchGitHubPRData(ctx context.Context, client *github.Client, prRef string) string {
 	// Format: owner/repo#123
 	hashIndex := strings.LastIndex(prRef, "#")
 	if hashIndex == -1 {
@@ -360,25 +445,6 @@ func fetchGitHubPRData(client *http.Client, prRef string) string {
 	repo := repoParts[1]
 
 	// Fetch PR metadata
-	prURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%s", owner, repo, prNumber)
-	req, err := http.NewRequest("GET", prURL, nil)
-	if err != nil {
-		return fmt.Sprintf("PR: #%s (unable to fetch)", prNumber)
-	}
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("User-Agent", "DevScope-DevAI")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Printf("⚠️ [DevAI] Failed to fetch PR %s: %v", prRef, err)
-		return fmt.Sprintf("PR: #%s (unable to fetch)", prNumber)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Sprintf("PR: #%s (not found or private repository)", prNumber)
-	}
-
 	var pr struct {
 		Number    int    `json:"number"`
 		Title     string `json:"title"`
@@ -401,21 +467,16 @@ func fetchGitHubPRData(client *http.Client, prRef string) string {
 		} `json:"head"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
-		return fmt.Sprintf("PR: #%s (unable to parse)", prNumber)
+	prPath := fmt.Sprintf("/repos/%s/%s/pulls/%s", url.PathEscape(owner), url.PathEscape(repo), url.PathEscape(prNumber))
+	if _, err := client.Get(ctx, prPath, &pr); err != nil {
+		if github.StatusCode(err) != 0 {
+			return fmt.Sprintf("PR: #%s (not found or private repository)", prNumber)
+		}
+		log.Printf("⚠️ [DevAI] Failed to fetch PR %s: %v", prRef, err)
+		return fmt.Sprintf("PR: #%s (unable to fetch)", prNumber)
 	}
 
 	// Fetch PR files (diffs)
-	filesURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%s/files", owner, repo, prNumber)
-	filesReq, _ := http.NewRequest("GET", filesURL, nil)
-	filesReq.Header.Set("Accept", "application/vnd.github.v3+json")
-	filesReq.Header.Set("User-Agent", "DevScope-DevAI")
-
-	filesResp, err := client.Do(filesReq)
-	if err != nil {
-		log.Printf("⚠️ [DevAI] Failed to fetch PR files %s: %v", prRef, err)
-	}
-
 	var files []struct {
 		Filename  string `json:"filename"`
 		Status    string `json:"status"`
@@ -424,9 +485,8 @@ func fetchGitHubPRData(client *http.Client, prRef string) string {
 		Patch     string `json:"patch"`
 	}
 
-	if filesResp != nil && filesResp.StatusCode == http.StatusOK {
-		json.NewDecoder(filesResp.Body).Decode(&files)
-		filesResp.Body.Close()
+	if _, err := client.Get(ctx, prPath+"/files", &files); err != nil {
+		log.Printf("⚠️ [DevAI] Failed to fetch PR files %s: %v", prRef, err)
 	}
 
 	// Build context
@@ -478,7 +538,7 @@ func fetchGitHubPRData(client *http.Client, prRef string) string {
 }
 
 // fetchGitHubFileContent fetches a file from a GitHub repository
-func fetchGitHubFileContent(client *http.Client, filePath string) string {
+func fetchGitHubFileContent(ctx context.Context, client *github.Client, filePath string) string {
 	// Format: owner/repo/path/to/file.ext
 	parts := strings.SplitN(filePath, "/", 3)
 	if len(parts) < 3 {
@@ -489,25 +549,6 @@ func fetchGitHubFileContent(client *http.Client, filePath string) string {
 	repo := parts[1]
 	path := parts[2]
 
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s", owner, repo, path)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return fmt.Sprintf("File: %s (unable to fetch)", filePath)
-	}
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("User-Agent", "DevScope-DevAI")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Printf("⚠️ [DevAI] Failed to fetch file %s: %v", filePath, err)
-		return fmt.Sprintf("File: %s (unable to fetch)", filePath)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Sprintf("File: %s (file not found or private repository)", filePath)
-	}
-
 	var fileInfo struct {
 		Name     string `json:"name"`
 		Path     string `json:"path"`
@@ -517,8 +558,13 @@ func fetchGitHubFileContent(client *http.Client, filePath string) string {
 		HTMLURL  string `json:"html_url"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&fileInfo); err != nil {
-		return fmt.Sprintf("File: %s (unable to parse response)", filePath)
+	contentPath := fmt.Sprintf("/repos/%s/%s/contents/%s", url.PathEscape(owner), url.PathEscape(repo), path)
+	if _, err := client.Get(ctx, contentPath, &fileInfo); err != nil {
+		if github.StatusCode(err) != 0 {
+			return fmt.Sprintf("File: %s (file not found or private repository)", filePath)
+		}
+		log.Printf("⚠️ [DevAI] Failed to fetch file %s: %v", filePath, err)
+		return fmt.Sprintf("File: %s (unable to fetch)", filePath)
 	}
 
 	// Size limit: 100KB
@@ -556,26 +602,7 @@ func fetchGitHubFileContent(client *http.Client, filePath string) string {
 }
 
 // fetchGitHubUserData fetches user information from GitHub API
-func fetchGitHubUserData(client *http.Client, username string) string {
-	url := fmt.Sprintf("https://api.github.com/users/%s", username)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return fmt.Sprintf("GitHub User: @%s (unable to fetch data)", username)
-	}
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("User-Agent", "DevScope-DevAI")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Printf("⚠️ [DevAI] Failed to fetch user %s: %v", username, err)
-		return fmt.Sprintf("GitHub User: @%s (unable to fetch data)", username)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Sprintf("GitHub User: @%s (user not found or API error)", username)
-	}
-
+func fetchGitHubUserData(ctx context.Context, client *github.Client, username string) string {
 	var user struct {
 		Login       string `json:"login"`
 		Name        string `json:"name"`
@@ -591,8 +618,12 @@ func fetchGitHubUserData(client *http.Client, username string) string {
 		HTMLURL     string `json:"html_url"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
-		return fmt.Sprintf("GitHub User: @%s (unable to parse data)", username)
+	if _, err := client.Get(ctx, "/users/"+url.PathEscape(username), &user); err != nil {
+		if github.StatusCode(err) != 0 {
+			return fmt.Sprintf("GitHub User: @%s (user not found or API error)", username)
+		}
+		log.Printf("⚠️ [DevAI] Failed to fetch user %s: %v", username, err)
+		return fmt.Sprintf("GitHub User: @%s (unable to fetch data)", username)
 	}
 
 	// Build detailed context
@@ -619,26 +650,7 @@ func fetchGitHubUserData(client *http.Client, username string) string {
 }
 
 // fetchGitHubRepoData fetches repository information from GitHub API
-func fetchGitHubRepoData(client *http.Client, fullName string) string {
-	url := fmt.Sprintf("https://api.github.com/repos/%s", fullName)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return fmt.Sprintf("Repository: %s (unable to fetch data)", fullName)
-	}
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("User-Agent", "DevScope-DevAI")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Printf("⚠️ [DevAI] Failed to fetch repo %s: %v", fullName, err)
-		return fmt.Sprintf("Repository: %s (unable to fetch data)", fullName)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Sprintf("Repository: %s (repository not found or API error)", fullName)
-	}
-
+func fetchGitHubRepoData(ctx context.Context, client *github.Client, fullName string) string {
 	var repo struct {
 		FullName        string   `json:"full_name"`
 		Description     string   `json:"description"`
@@ -658,8 +670,12 @@ func fetchGitHubRepoData(client *http.Client, fullName string) string {
 		} `json:"license"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&repo); err != nil {
-		return fmt.Sprintf("Repository: %s (unable to parse data)", fullName)
+	if _, err := client.Get(ctx, "/repos/"+fullName, &repo); err != nil {
+		if github.StatusCode(err) != 0 {
+			return fmt.Sprintf("Repository: %s (repository not found or API error)", fullName)
+		}
+		log.Printf("⚠️ [DevAI] Failed to fetch repo %s: %v", fullName, err)
+		return fmt.Sprintf("Repository: %s (unable to fetch data)", fullName)
 	}
 
 	// Build detailed context
@@ -684,7 +700,7 @@ func fetchGitHubRepoData(client *http.Client, fullName string) string {
 	info.WriteString(fmt.Sprintf("Repository URL: %s\n", repo.HTMLURL))
 
 	// Fetch recent commits for activity feed
-	recentActivity := fetchRecentCommits(client, fullName)
+	recentActivity := fetchRecentCommits(ctx, client, fullName)
 	if recentActivity != "" {
 		info.WriteString("\n--- Recent Activity ---\n")
 		info.WriteString(recentActivity)
@@ -695,25 +711,7 @@ func fetchGitHubRepoData(client *http.Client, fullName string) string {
 }
 
 // fetchRecentCommits fetches the 5 most recent commits
-func fetchRecentCommits(client *http.Client, fullName string) string {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/commits?per_page=5", fullName)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return ""
-	}
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("User-Agent", "DevScope-DevAI")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return ""
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return ""
-	}
-
+func fetchRecentCommits(ctx context.Context, client *github.Client, fullName string) string {
 	var commits []struct {
 		SHA    string `json:"sha"`
 		Commit struct {
@@ -725,7 +723,7 @@ func fetchRecentCommits(client *http.Client, fullName string) string {
 		} `json:"commit"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&commits); err != nil {
+	if _, err := client.Get(ctx, "/repos/"+fullName+"/commits?per_page=5", &commits); err != nil {
 		return ""
 	}
 
@@ -761,14 +759,6 @@ func (s *Server) DevAISearchReposHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Search GitHub for repositories
-	url := fmt.Sprintf("https://api.github.com/search/repositories?q=%s&per_page=5&sort=stars", query)
-	resp, err := http.Get(url)
-	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, SearchResponse{Error: true, Message: "Failed to search repositories"})
-		return
-	}
-	defer resp.Body.Close()
-
 	var result struct {
 		Items []struct {
 			FullName    string `json:"full_name"`
@@ -779,8 +769,10 @@ func (s *Server) DevAISearchReposHandler(w http.ResponseWriter, r *http.Request)
 		} `json:"items"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		writeJSON(w, http.StatusInternalServerError, SearchResponse{Error: true, Message: "Failed to parse search results"})
+	searchPath := "/search/repositories?" + url.Values{"q": {query}, "per_page": {"5"}, "sort": {"stars"}}.Encode()
+	if _, err := s.service.GitHub().Get(r.Context(), searchPath, &result); err != nil {
+		log.Printf("⚠️ [DevAI] Repository search failed: %v", err)
+		writeJSON(w, http.StatusInternalServerError, SearchResponse{Error: true, Message: "Failed to search repositories"})
 		return
 	}
 
@@ -812,14 +804,6 @@ func (s *Server) DevAISearchUsersHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Search GitHub for users
-	url := fmt.Sprintf("https://api.github.com/search/users?q=%s&per_page=5", query)
-	resp, err := http.Get(url)
-	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, SearchResponse{Error: true, Message: "Failed to search users"})
-		return
-	}
-	defer resp.Body.Close()
-
 	var result struct {
 		Items []struct {
 			Login     string `json:"login"`
@@ -829,8 +813,10 @@ func (s *Server) DevAISearchUsersHandler(w http.ResponseWriter, r *http.Request)
 		} `json:"items"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		writeJSON(w, http.StatusInternalServerError, SearchResponse{Error: true, Message: "Failed to parse search results"})
+	searchPath := "/search/users?" + url.Values{"q": {query}, "per_page": {"5"}}.Encode()
+	if _, err := s.service.GitHub().Get(r.Context(), searchPath, &result); err != nil {
+		log.Printf("⚠️ [DevAI] User search failed: %v", err)
+		writeJSON(w, http.StatusInternalServerError, SearchResponse{Error: true, Message: "Failed to search users"})
 		return
 	}
 
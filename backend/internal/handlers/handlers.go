@@ -4,32 +4,47 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github-api/backend/internal/breaker"
 	"github-api/backend/internal/cache"
 	"github-api/backend/internal/config"
+	"github-api/backend/internal/github"
 	"github-api/backend/internal/models"
 	"github-api/backend/internal/repository"
+	"github-api/backend/internal/retry"
 	"github-api/backend/internal/service"
 )
 
+// lookupTimeout bounds a user lookup, including every GitHub call it makes.
+// A lookup also stops as soon as the client disconnects.
+const lookupTimeout = 30 * time.Second
+
 // Server holds the application state
 type Server struct {
 	service        *service.GitHubService
 	rankingService *service.RankingService
-	cache          *cache.Cache
+	cache          *cache.Cache[string, models.GitHubUser]
 	config         *config.Config
 	startTime      time.Time
 	aiLimiter      *RateLimiter
 	searchHandler  *SearchHandler
 	devaiRepo      *repository.DevAIRepository
+	aiClient       *http.Client
+	aiRetries      *retry.Transport
+	aiBreaker      *breaker.Breaker
 }
 
 // NewServer creates a new server instance
-func NewServer(cfg *config.Config, c *cache.Cache, svc *service.GitHubService, rankingSvc *service.RankingService, searchHandler *SearchHandler) *Server {
+func NewServer(cfg *config.Config, c *cache.Cache[string, models.GitHubUser], svc *service.GitHubService, rankingSvc *service.RankingService, searchHandler *SearchHandler) *Server {
+	aiRetries := retry.NewTransport("nvidia", nvidiaRetryPolicy, nil)
+	aiBreaker := breaker.New("nvidia", nvidiaBreakerThreshold, nvidiaBreakerCooldown)
 	return &Server{
 		service:        svc,
 		rankingService: rankingSvc,
@@ -39,6 +54,9 @@ func NewServer(cfg *config.Config, c *cache.Cache, svc *service.GitHubService, r
 		// AI rate limit: 10 requests per minute per IP
 		aiLimiter:     NewRateLimiter(10, time.Minute),
 		searchHandler: searchHandler,
+		aiClient:      &http.Client{Transport: breaker.NewTransport(aiBreaker, aiRetries, nvidiaTimeout)},
+		aiRetries:     aiRetries,
+		aiBreaker:     aiBreaker,
 	}
 }
 
@@ -60,16 +78,27 @@ func (s *Server) HomeHandler(w http.ResponseWriter, r *http.Request) {
 			"Native Go performance",
 		},
 		"endpoints": map[string]string{
-			"GET /api/status/{username}":        "Fetch GitHub status for a username",
-			"GET /api/user/{username}/extended": "Fetch extended user info with tech stack & streak",
-			"POST /api/status":                  "Fetch GitHub status (JSON body)",
-			"POST /api/batch":                   "Fetch status for multiple users",
-			"POST /api/ai/compare":              "AI-powered user comparison",
-			"POST /api/ai/analyze":              "AI-powered single user/repo analysis",
-			"GET /api/search/history":           "Get user's search history (authenticated)",
-			"GET /api/health":                   "Health check with cache stats",
-			"GET /api/cache/stats":              "Cache statistics",
-			"POST /api/cache/clear":             "Clear cache",
+			"GET /api/status/{username}":                 "Fetch GitHub status for a username",
+			"GET /api/user/{username}/extended":          "Fetch extended user info with tech stack & streak",
+			"GET /api/user/{username}/activity":          "Activity heatmap, event breakdown, 30-day trend & top repos",
+			"GET /api/user/{username}/network":           "Collaboration graph as JSON or GraphML (?format=graphml)",
+			"GET /api/repos/{owner}/{repo}":              "Repository analytics with commits, issues, languages & contributors",
+			"GET /api/repos/{owner}/{repo}/health":       "Scored repository health checklist",
+			"GET /api/repos/{owner}/{repo}/contributors": "Bus factor, commit share and maintainer churn",
+			"GET /api/repos/{owner}/{repo}/velocity":     "Weekly issue and pull request response, close and merge times",
+			"POST /api/status":                           "Fetch GitHub status (JSON body)",
+			"POST /api/batch":                            "Fetch status for multiple users",
+			"POST /api/compare":                          "Shared followers, mutual follows, shared repos & stars, language similarity",
+			"POST /api/ai/compare":                       "AI-powered user comparison",
+			"POST /api/ai/analyze":                       "AI-powered single user/repo analysis",
+			"GET /api/search/history":                    "Get user's search history (authenticated)",
+			"GET /api/health":                            "Health check with cache stats",
+			"GET /api/cache/stats":                       "Cache statistics",
+			"POST /api/cache/clear":                      "Clear cache (admin)",
+			"GET /api/admin/cache/keys":                  "List cache keys with age and TTL (admin)",
+			"GET /api/admin/cache/entry":                 "Inspect a single cache entry (admin)",
+			"POST /api/admin/cache/invalidate":           "Invalidate a cache key or prefix (admin)",
+			"POST /api/admin/cache/config":               "Change cache TTL and max size (admin)",
 		},
 	}
 	writeJSON(w, http.StatusOK, response)
@@ -89,6 +118,10 @@ func (s *Server) HealthHandler(w http.ResponseWriter, r *http.Request) {
 		CacheEnabled:  true,
 		CacheSize:     s.cache.Size(),
 		UptimeSeconds: fmt.Sprintf("%.2f", uptime),
+
+		GitHubRateLimit: s.service.GitHub().RateLimit(),
+		Retries:         []models.RetryStats{s.service.GitHub().Retries(), s.aiRetries.Stats()},
+		Breakers:        []models.BreakerStatus{s.service.GitHub().Breaker(), s.aiBreaker.Status()},
 	}
 
 	// Write response immediately
@@ -97,13 +130,7 @@ func (s *Server) HealthHandler(w http.ResponseWriter, r *http.Request) {
 
 // CacheStatsHandler returns cache statistics
 func (s *Server) CacheStatsHandler(w http.ResponseWriter, r *http.Request) {
-	writeJSON(w, http.StatusOK, s.cache.Stats())
-}
-
-// CacheClearHandler clears the cache
-func (s *Server) CacheClearHandler(w http.ResponseWriter, r *http.Request) {
-	s.cache.Clear()
-	writeJSON(w, http.StatusOK, map[string]string{"message": "Cache cleared successfully"})
+	writeJSON(w, http.StatusOK, s.service.CacheStats())
 }
 
 // GetStatusByPathHandler handles GET /api/status/{username}
@@ -121,14 +148,13 @@ func (s *Server) GetStatusByPathHandler(w http.ResponseWriter, r *http.Request)
 		go s.searchHandler.LogSearchHistory(context.Background(), user.ID, username, "status")
 	}
 
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+
 	useCache := r.URL.Query().Get("no_cache") != "true"
-	result, err := s.service.GetUserStatus(username, useCache)
+	result, err := s.service.GetUserStatus(ctx, username, useCache)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if strings.Contains(err.Error(), "404") {
-			status = http.StatusNotFound
-		}
-		writeJSON(w, status, result)
+		writeServiceError(w, result, err)
 		return
 	}
 
@@ -169,14 +195,13 @@ func (s *Server) GetStatusByBodyHandler(w http.ResponseWriter, r *http.Request)
 		go s.searchHandler.LogSearchHistory(context.Background(), user.ID, username, "status")
 	}
 
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+
 	useCache := r.URL.Query().Get("no_cache") != "true"
-	result, err := s.service.GetUserStatus(username, useCache)
+	result, err := s.service.GetUserStatus(ctx, username, useCache)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if strings.Contains(err.Error(), "404") {
-			status = http.StatusNotFound
-		}
-		writeJSON(w, status, result)
+		writeServiceError(w, result, err)
 		return
 	}
 
@@ -214,11 +239,16 @@ func (s *Server) BatchHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	result := s.service.GetBatchStatus(req.Usernames)
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+
+	result := s.service.GetBatchStatus(ctx, req.Usernames)
 	writeJSON(w, http.StatusOK, result)
 }
 
-// GetExtendedUserHandler handles GET /api/user/{username}/extended
+// GetExtendedUserHandler handles GET /api/user/{username}/extended[?tz=Area/City]
+// with optional weighted=true, exclude_forks=true and exclude_archived=true
+// to shape the tech stack
 func (s *Server) GetExtendedUserHandler(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/api/user/")
 	path = strings.TrimSuffix(path, "/extended")
@@ -234,14 +264,25 @@ func (s *Server) GetExtendedUserHandler(w http.ResponseWriter, r *http.Request)
 		go s.searchHandler.LogSearchHistory(context.Background(), user.ID, username, "extended")
 	}
 
-	useCache := r.URL.Query().Get("no_cache") != "true"
-	result, err := s.service.GetExtendedUserInfo(username, useCache)
+	loc, err := requestLocation(r)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if strings.Contains(err.Error(), "404") {
-			status = http.StatusNotFound
-		}
-		writeJSON(w, status, models.APIResponse{Error: true, Message: err.Error()})
+		writeJSON(w, http.StatusBadRequest, models.APIResponse{Error: true, Message: err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+
+	query := r.URL.Query()
+	useCache := query.Get("no_cache") != "true"
+	opts := service.TechStackOptions{
+		Weighted:        query.Get("weighted") == "true",
+		ExcludeForks:    query.Get("exclude_forks") == "true",
+		ExcludeArchived: query.Get("exclude_archived") == "true",
+	}
+	result, err := s.service.GetExtendedUserInfo(ctx, username, useCache, loc, opts)
+	if err != nil {
+		writeServiceError(w, &models.APIResponse{Error: true, Message: err.Error()}, err)
 		return
 	}
 
@@ -263,6 +304,66 @@ func (s *Server) GetExtendedUserHandler(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, http.StatusOK, map[string]interface{}{"error": false, "data": result})
 }
 
+// requestLocation returns the time zone for daily activity: the tz query
+// parameter, else the signed-in user's saved timezone, else UTC
+func requestLocation(r *http.Request) (*time.Location, error) {
+	name := r.URL.Query().Get("tz")
+	if name == "" {
+		if user, ok := r.Context().Value("user").(*models.User); ok {
+			name = user.Timezone
+		}
+	}
+	if name == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return loc, nil
+}
+
+// writeServiceError writes a failed lookup with a status derived from err:
+// 404 for unknown users and repositories, 429 with Retry-After when GitHub's rate limit is
+// exhausted, 503 with Retry-After while GitHub's circuit breaker is open,
+// 504 when the lookup ran out of time, and 500 otherwise
+func writeServiceError(w http.ResponseWriter, resp *models.APIResponse, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrRepoNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, github.ErrRateLimited):
+		status = http.StatusTooManyRequests
+		if reset := github.RateLimitReset(err); !reset.IsZero() {
+			resp.ResetAt = &reset
+			setRetryAfter(w, reset)
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		status = http.StatusGatewayTimeout
+	case errors.Is(err, breaker.ErrOpen):
+		resp.Message = "GitHub is temporarily unavailable, please try again shortly"
+		writeUnavailable(w, err, resp)
+		return
+	}
+	writeJSON(w, status, resp)
+}
+
+// writeUnavailable writes a 503 with Retry-After for a call an open circuit
+// breaker rejected without contacting the upstream
+func writeUnavailable(w http.ResponseWriter, err error, data interface{}) {
+	if retryAt := breaker.RetryAt(err); !retryAt.IsZero() {
+		setRetryAfter(w, retryAt)
+	}
+	writeJSON(w, http.StatusServiceUnavailable, data)
+}
+
+// setRetryAfter sets Retry-After to the whole seconds until at (at least 1)
+func setRetryAfter(w http.ResponseWriter, at time.Time) {
+	retryAfter := max(math.Ceil(time.Until(at).Seconds()), 1)
+	w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter)))
+}
+
 // writeJSON writes JSON response
 func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
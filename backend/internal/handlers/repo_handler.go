@@ -0,0 +1,129 @@
+// Package handlers provides repository analytics HTTP handlers
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github-api/backend/internal/models"
+	"github-api/backend/internal/service"
+)
+
+// ReposHandler routes GET /api/repos/{owner}/{repo}[/health|/contributors|/velocity]
+func (s *Server) ReposHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, models.APIResponse{Error: true, Message: "Method not allowed"})
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/repos/"), "/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		writeJSON(w, http.StatusBadRequest, models.APIResponse{Error: true, Message: "Expected /api/repos/{owner}/{repo}"})
+		return
+	}
+	owner, repo := parts[0], parts[1]
+
+	switch strings.Join(parts[2:], "/") {
+	case "":
+		s.repoAnalyticsHandler(w, r, owner, repo)
+	case "health":
+		s.repoHealthHandler(w, r, owner, repo)
+	case "contributors":
+		s.repoContributorsHandler(w, r, owner, repo)
+	case "velocity":
+		s.repoVelocityHandler(w, r, owner, repo)
+	default:
+		writeJSON(w, http.StatusNotFound, models.APIResponse{Error: true, Message: "Not found"})
+	}
+}
+
+// repoAnalyticsHandler handles GET /api/repos/{owner}/{repo}
+func (s *Server) repoAnalyticsHandler(w http.ResponseWriter, r *http.Request, owner, repo string) {
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+
+	useCache := r.URL.Query().Get("no_cache") != "true"
+	result, err := s.service.GetRepoAnalytics(ctx, owner, repo, useCache)
+	if err != nil {
+		writeServiceError(w, &models.APIResponse{Error: true, Message: err.Error()}, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.APIResponse{Error: false, Data: result})
+}
+
+// repoHealthHandler handles GET /api/repos/{owner}/{repo}/health
+func (s *Server) repoHealthHandler(w http.ResponseWriter, r *http.Request, owner, repo string) {
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+
+	useCache := r.URL.Query().Get("no_cache") != "true"
+	result, err := s.service.GetRepoHealth(ctx, owner, repo, useCache)
+	if err != nil {
+		writeServiceError(w, &models.APIResponse{Error: true, Message: err.Error()}, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.APIResponse{Error: false, Data: result})
+}
+
+// repoContributorsHandler handles GET /api/repos/{owner}/{repo}/contributors,
+// measuring contributor concentration over the last ?days=N days (90 by default)
+func (s *Server) repoContributorsHandler(w http.ResponseWriter, r *http.Request, owner, repo string) {
+	days := service.DefaultContributorWindow
+	if d := r.URL.Query().Get("days"); d != "" {
+		parsed, err := strconv.Atoi(d)
+		if err != nil || parsed < 1 || parsed > service.MaxContributorWindow {
+			writeJSON(w, http.StatusBadRequest, models.APIResponse{
+				Error:   true,
+				Message: fmt.Sprintf("days must be between 1 and %d", service.MaxContributorWindow),
+			})
+			return
+		}
+		days = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+
+	useCache := r.URL.Query().Get("no_cache") != "true"
+	result, err := s.service.GetContributorConcentration(ctx, owner, repo, days, useCache)
+	if err != nil {
+		writeServiceError(w, &models.APIResponse{Error: true, Message: err.Error()}, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.APIResponse{Error: false, Data: result})
+}
+
+// repoVelocityHandler handles GET /api/repos/{owner}/{repo}/velocity, returning
+// weekly issue and pull request metrics over the last ?weeks=N weeks (12 by default)
+func (s *Server) repoVelocityHandler(w http.ResponseWriter, r *http.Request, owner, repo string) {
+	weeks := service.DefaultVelocityWeeks
+	if wk := r.URL.Query().Get("weeks"); wk != "" {
+		parsed, err := strconv.Atoi(wk)
+		if err != nil || parsed < 1 || parsed > service.MaxVelocityWeeks {
+			writeJSON(w, http.StatusBadRequest, models.APIResponse{
+				Error:   true,
+				Message: fmt.Sprintf("weeks must be between 1 and %d", service.MaxVelocityWeeks),
+			})
+			return
+		}
+		weeks = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), lookupTimeout)
+	defer cancel()
+
+	useCache := r.URL.Query().Get("no_cache") != "true"
+	result, err := s.service.GetRepoVelocity(ctx, owner, repo, weeks, useCache)
+	if err != nil {
+		writeServiceError(w, &models.APIResponse{Error: true, Message: err.Error()}, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.APIResponse{Error: false, Data: result})
+}
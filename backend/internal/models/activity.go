@@ -0,0 +1,77 @@
+// Package models defines data structures for developer activity
+package models
+
+// ActivityBreakdown counts events by kind. Reviews include review comments,
+// issues include issue comments; Other counts every remaining event type.
+type ActivityBreakdown struct {
+	Push        int `json:"push"`
+	PullRequest int `json:"pull_request"`
+	Review      int `json:"review"`
+	Issue       int `json:"issue"`
+	Release     int `json:"release"`
+	Other       int `json:"other"`
+}
+
+// ActivityTrendDay is one day of an activity trend: the events that day and
+// the events in the 30 days ending with it
+type ActivityTrendDay struct {
+	Date      string `json:"date"` // YYYY-MM-DD
+	Events    int    `json:"events"`
+	Rolling30 int    `json:"rolling_30"`
+}
+
+// ActiveRepo is a repository a user was active in, with their event count
+type ActiveRepo struct {
+	Name       string `json:"name"`
+	Events     int    `json:"events"`
+	LastActive string `json:"last_active"`
+}
+
+// UserActivity describes when and where a user works, from their recent
+// public events. Heatmap counts events by weekday (0 is Sunday) and hour in
+// TimeZone. Trend covers the last 30 days, oldest first, and TrendChange is
+// the percentage change of those 30 days over the 30 before them (0 when
+// there were no events before).
+type UserActivity struct {
+	Username    string             `json:"username"`
+	TimeZone    string             `json:"time_zone"`
+	TotalEvents int                `json:"total_events"`
+	Since       string             `json:"since,omitempty"`
+	Heatmap     [7][24]int         `json:"heatmap"`
+	Breakdown   ActivityBreakdown  `json:"breakdown"`
+	Trend       []ActivityTrendDay `json:"trend"`
+	TrendChange float64            `json:"trend_change"`
+	TopRepos    []ActiveRepo       `json:"top_repos"`
+	Truncated   bool               `json:"truncated,omitempty"`
+}
+
+// NetworkNode is a person or repository in a collaboration graph. ID is
+// "user:{login}" or "repo:{owner}/{name}"; Weight sums its edges.
+type NetworkNode struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"` // "user" or "repo"
+	Label  string `json:"label"`
+	Weight int    `json:"weight"`
+}
+
+// NetworkEdge links two nodes of a collaboration graph. Weight counts the
+// commits or events behind it: Kind "contributor" links a person to a
+// repository they committed to, "activity" the developer to a repository
+// they opened pull requests, reviewed or commented in, and "review" or
+// "issue_comment" the developer to the author they responded to.
+type NetworkEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Kind   string `json:"kind"`
+	Weight int    `json:"weight"`
+}
+
+// UserNetwork is a weighted graph of a developer's collaborators and the
+// repositories they share. Partial is set when some contributor lists could
+// not be fetched.
+type UserNetwork struct {
+	Username string        `json:"username"`
+	Nodes    []NetworkNode `json:"nodes"`
+	Edges    []NetworkEdge `json:"edges"`
+	Partial  bool          `json:"partial,omitempty"`
+}
@@ -0,0 +1,65 @@
+// Package models defines data structures for user comparisons
+package models
+
+// UserConnections are the accounts a user follows and is followed by and
+// the repositories they starred, as logins and "owner/repo" names. Truncated
+// is set when a listing stopped at the page cap.
+type UserConnections struct {
+	Followers []string `json:"followers"`
+	Following []string `json:"following"`
+	Starred   []string `json:"starred"`
+	Truncated bool     `json:"truncated,omitempty"`
+}
+
+// Overlap is what several users have in common: the size of the overlap and
+// up to the first 50 items of it in alphabetical order
+type Overlap struct {
+	Count int      `json:"count"`
+	Items []string `json:"items"`
+}
+
+// ComparedUser summarises the lists a comparison read for one user
+type ComparedUser struct {
+	Login            string `json:"login"`
+	Followers        int    `json:"followers"`
+	Following        int    `json:"following"`
+	Starred          int    `json:"starred"`
+	ContributedRepos int    `json:"contributed_repos"`
+	Truncated        bool   `json:"truncated,omitempty"`
+}
+
+// ComparisonPair relates two of the compared users. Follow directions are
+// read from either user's lists. StarSimilarity is the Jaccard index of
+// their starred repositories and LanguageSimilarity the weighted Jaccard
+// index of their repositories' primary languages, both from 0 to 1.
+type ComparisonPair struct {
+	Users              [2]string `json:"users"`
+	FirstFollowsSecond bool      `json:"first_follows_second"`
+	SecondFollowsFirst bool      `json:"second_follows_first"`
+	MutualFollow       bool      `json:"mutual_follow"`
+	SharedFollowers    Overlap   `json:"shared_followers"`
+	SharedFollowing    Overlap   `json:"shared_following"`
+	SharedRepos        Overlap   `json:"shared_repos"`
+	SharedStars        Overlap   `json:"shared_stars"`
+	StarSimilarity     float64   `json:"star_similarity"`
+	LanguageSimilarity float64   `json:"language_similarity"`
+}
+
+// CommonGround is what every compared user shares
+type CommonGround struct {
+	Followers Overlap `json:"followers"`
+	Following Overlap `json:"following"`
+	Repos     Overlap `json:"repos"`
+	Stars     Overlap `json:"stars"`
+}
+
+// UserComparison relates two or more users through their connections,
+// contributions, stars and languages. Partial is set when a user's events
+// could not be fetched, leaving out the repositories they only contributed
+// to through events.
+type UserComparison struct {
+	Users   []ComparedUser   `json:"users"`
+	Pairs   []ComparisonPair `json:"pairs"`
+	Common  CommonGround     `json:"common"`
+	Partial bool             `json:"partial,omitempty"`
+}
@@ -1,6 +1,8 @@
 // Package models defines data structures
 package models
 
+import "time"
+
 // GitHubUser represents the GitHub user data structure
 type GitHubUser struct {
 	ID                int64  `json:"id"`
@@ -38,32 +40,108 @@ type Plan struct {
 // GitHubRepo represents a GitHub repository
 type GitHubRepo struct {
 	Name            string `json:"name"`
+	FullName        string `json:"full_name"`
 	Language        string `json:"language"`
 	StargazersCount int    `json:"stargazers_count"`
 	ForksCount      int    `json:"forks_count"`
 	Description     string `json:"description"`
+	Fork            bool   `json:"fork"`
+	Archived        bool   `json:"archived"`
 	UpdatedAt       string `json:"updated_at"`
+	PushedAt        string `json:"pushed_at"`
 }
 
-// GitHubEvent represents a GitHub event for streak calculation
+// GitHubEvent represents a GitHub event for streak and activity analysis
 type GitHubEvent struct {
-	Type      string `json:"type"`
-	CreatedAt string `json:"created_at"`
+	Type      string       `json:"type"`
+	CreatedAt string       `json:"created_at"`
+	Repo      EventRepo    `json:"repo"`
+	Payload   EventPayload `json:"payload"`
+}
+
+// EventRepo is the repository an event happened in
+type EventRepo struct {
+	Name string `json:"name"` // owner/repo
+}
+
+// EventPayload keeps the parts of an event's payload that identify who else
+// was involved: the author of the pull request or issue acted on
+type EventPayload struct {
+	Action      string     `json:"action,omitempty"`
+	PullRequest *EventItem `json:"pull_request,omitempty"`
+	Issue       *EventItem `json:"issue,omitempty"`
+}
+
+// EventItem is the pull request or issue an event acted on
+type EventItem struct {
+	Number int       `json:"number"`
+	User   RepoOwner `json:"user"`
+}
+
+// RepoList is a user's repositories; Truncated is set when pagination
+// stopped at the configured page cap
+type RepoList struct {
+	Repos     []GitHubRepo `json:"repos"`
+	Truncated bool         `json:"truncated"`
 }
 
-// TechStack represents language statistics
+// EventList is a user's recent events; Truncated is set when pagination
+// stopped at the configured page cap
+type EventList struct {
+	Events    []GitHubEvent `json:"events"`
+	Truncated bool          `json:"truncated"`
+}
+
+// TechStack represents language statistics. Languages counts repositories
+// by their primary language. In "bytes" mode Breakdown weighs every language
+// by the bytes of code GitHub detected across the ReposAnalyzed most recently
+// pushed repositories, and Recent does the same for repositories pushed in
+// the last 12 months.
 type TechStack struct {
 	Languages   map[string]int `json:"languages"`
 	TopLanguage string         `json:"top_language"`
 	TotalRepos  int            `json:"total_repos"`
+	Truncated   bool           `json:"truncated,omitempty"`
+
+	Mode          string          `json:"mode"`
+	Breakdown     []LanguageShare `json:"breakdown,omitempty"`
+	Recent        []LanguageShare `json:"recent,omitempty"`
+	ReposAnalyzed int             `json:"repos_analyzed,omitempty"`
+}
+
+// LanguageShare is one language's share of the bytes in a set of repositories
+type LanguageShare struct {
+	Language string  `json:"language"`
+	Bytes    int64   `json:"bytes"`
+	Percent  float64 `json:"percent"`
 }
 
-// StreakInfo represents contribution streak data
+// StreakInfo represents contribution streak data. Source is "calendar" when
+// computed from the contribution calendar (which is then included for
+// rendering as a heatmap) and "events" when only recent public events were
+// available.
 type StreakInfo struct {
-	CurrentStreak int    `json:"current_streak"`
-	LongestStreak int    `json:"longest_streak"`
-	TotalDays     int    `json:"total_days"`
-	LastActive    string `json:"last_active"`
+	CurrentStreak      int               `json:"current_streak"`
+	LongestStreak      int               `json:"longest_streak"`
+	TotalDays          int               `json:"total_days"`
+	LastActive         string            `json:"last_active"`
+	Truncated          bool              `json:"truncated,omitempty"`
+	Source             string            `json:"source,omitempty"`
+	TotalContributions int               `json:"total_contributions,omitempty"`
+	Calendar           []ContributionDay `json:"calendar,omitempty"`
+}
+
+// ContributionDay is one day of a user's contribution calendar
+type ContributionDay struct {
+	Date  string `json:"date"` // YYYY-MM-DD
+	Count int    `json:"count"`
+	Level int    `json:"level"` // 0 (none) to 4 (top quartile), as on GitHub's heatmap
+}
+
+// ContributionCalendar is a user's day-by-day contributions for the past year
+type ContributionCalendar struct {
+	TotalContributions int               `json:"total_contributions"`
+	Days               []ContributionDay `json:"days"`
 }
 
 // UserExtendedInfo represents extended user information
@@ -77,8 +155,12 @@ type UserExtendedInfo struct {
 type APIResponse struct {
 	Error   bool        `json:"error"`
 	Cached  bool        `json:"cached,omitempty"`
+	Stale   bool        `json:"stale,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
 	Message string      `json:"message,omitempty"`
+
+	// ResetAt is set when GitHub's rate limit is exhausted
+	ResetAt *time.Time `json:"reset_at,omitempty"`
 }
 
 // BatchResponse represents a batch request response
@@ -105,12 +187,86 @@ type HealthResponse struct {
 	CacheEnabled  bool   `json:"cache_enabled"`
 	CacheSize     int    `json:"cache_size"`
 	UptimeSeconds string `json:"uptime_seconds"`
+
+	GitHubRateLimit []RateLimitStatus `json:"github_rate_limit,omitempty"`
+	Retries         []RetryStats      `json:"retries,omitempty"`
+	Breakers        []BreakerStatus   `json:"breakers,omitempty"`
+}
+
+// BreakerStatus reports the circuit breaker guarding one upstream API
+type BreakerStatus struct {
+	Upstream string     `json:"upstream"`
+	State    string     `json:"state"` // closed, open or half-open
+	Failures int        `json:"consecutive_failures"`
+	Trips    int64      `json:"trips"`    // times the breaker opened
+	Rejected int64      `json:"rejected"` // calls failed fast while open
+	RetryAt  *time.Time `json:"retry_at,omitempty"`
+}
+
+// RetryStats counts retries of transient failures against one upstream API
+type RetryStats struct {
+	Upstream  string `json:"upstream"`
+	Retries   int64  `json:"retries"`   // extra attempts sent
+	Recovered int64  `json:"recovered"` // requests that succeeded on a retry
+	Exhausted int64  `json:"exhausted"` // requests that failed after the last attempt
+}
+
+// RateLimitStatus reports the GitHub API quota of one token for one resource
+type RateLimitStatus struct {
+	Token        string     `json:"token"` // masked
+	Resource     string     `json:"resource"`
+	Limit        int        `json:"limit"`
+	Remaining    int        `json:"remaining"`
+	Used         int        `json:"used"`
+	ResetAt      time.Time  `json:"reset_at"`
+	BlockedUntil *time.Time `json:"blocked_until,omitempty"`
+	Requests     int64      `json:"requests"`           // sent by this server
+	Disabled     bool       `json:"disabled,omitempty"` // rejected with 401
 }
 
 // CacheStats represents cache statistics
 type CacheStats struct {
-	Size    int    `json:"size"`
-	Hits    int64  `json:"hits"`
-	Misses  int64  `json:"misses"`
-	HitRate string `json:"hit_rate"`
+	Size           int     `json:"size"`
+	MaxSize        int     `json:"max_size"`
+	TTLSeconds     float64 `json:"ttl_seconds"`
+	Hits           int64   `json:"hits"`
+	StaleHits      int64   `json:"stale_hits"`
+	PersistentHits int64   `json:"persistent_hits"`
+	NegativeHits   int64   `json:"negative_hits"`
+	Misses         int64   `json:"misses"`
+	Revalidations  int64   `json:"revalidations"`
+	NotModified    int64   `json:"not_modified"`
+	Evictions      int64   `json:"evictions"`
+	Expirations    int64   `json:"expirations"`
+	HitRate        string  `json:"hit_rate"`
+	Coalesced      int64   `json:"coalesced"`
+
+	GitHubRateLimit []RateLimitStatus `json:"github_rate_limit,omitempty"`
+}
+
+// CacheKeyInfo describes a single cache entry for administration
+type CacheKeyInfo struct {
+	Key                 string  `json:"key"`
+	AgeSeconds          float64 `json:"age_seconds"`
+	TTLRemainingSeconds float64 `json:"ttl_remaining_seconds"`
+	Stale               bool    `json:"stale"`
+	ETag                string  `json:"etag,omitempty"`
+	LastModified        string  `json:"last_modified,omitempty"`
+}
+
+// CacheEntry is a cached value together with its metadata
+type CacheEntry struct {
+	CacheKeyInfo
+	Data interface{} `json:"data"`
+}
+
+// CacheRecord represents a raw GitHub payload persisted in the second-level cache
+type CacheRecord struct {
+	Kind      string    `json:"kind" db:"kind"`
+	Key       string    `json:"key" db:"cache_key"`
+	Data      string    `json:"data" db:"data"` // JSONB as string
+	FetchedAt time.Time `json:"fetched_at" db:"fetched_at"`
+
+	ETag         string `json:"etag" db:"etag"`
+	LastModified string `json:"last_modified" db:"last_modified"`
 }
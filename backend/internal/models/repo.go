@@ -0,0 +1,256 @@
+// Package models defines data structures for repository analytics
+package models
+
+import "time"
+
+// RepoOwner is the account that owns a repository or authored an item in it
+type RepoOwner struct {
+	Login     string `json:"login"`
+	AvatarURL string `json:"avatar_url"`
+	HTMLURL   string `json:"html_url"`
+}
+
+// RepoLicense is a repository's detected license
+type RepoLicense struct {
+	Name string `json:"name"`
+}
+
+// RepoDetails represents a single repository from /repos/{owner}/{repo}
+type RepoDetails struct {
+	ID              int64        `json:"id"`
+	Name            string       `json:"name"`
+	FullName        string       `json:"full_name"`
+	Description     string       `json:"description"`
+	HTMLURL         string       `json:"html_url"`
+	CloneURL        string       `json:"clone_url"`
+	Homepage        string       `json:"homepage"`
+	Language        string       `json:"language"`
+	StargazersCount int          `json:"stargazers_count"`
+	ForksCount      int          `json:"forks_count"`
+	WatchersCount   int          `json:"watchers_count"`
+	OpenIssuesCount int          `json:"open_issues_count"`
+	Private         bool         `json:"private"`
+	Fork            bool         `json:"fork"`
+	Archived        bool         `json:"archived"`
+	Topics          []string     `json:"topics"`
+	Size            int          `json:"size"`
+	DefaultBranch   string       `json:"default_branch"`
+	License         *RepoLicense `json:"license"`
+	Owner           RepoOwner    `json:"owner"`
+	CreatedAt       string       `json:"created_at"`
+	UpdatedAt       string       `json:"updated_at"`
+	PushedAt        string       `json:"pushed_at"`
+}
+
+// RepoCommit is one commit from a repository's history
+type RepoCommit struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+			Date  string `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+	// Author is nil when the commit email is not linked to a GitHub account
+	Author *RepoOwner `json:"author"`
+}
+
+// RepoLabel is a label applied to an issue
+type RepoLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// RepoIssue is an issue or pull request; GitHub's issues listing returns
+// both, with PullRequest set on pull requests
+type RepoIssue struct {
+	ID          int64       `json:"id"`
+	Number      int         `json:"number"`
+	Title       string      `json:"title"`
+	State       string      `json:"state"`
+	HTMLURL     string      `json:"html_url"`
+	Comments    int         `json:"comments"`
+	Labels      []RepoLabel `json:"labels"`
+	User        RepoOwner   `json:"user"`
+	CreatedAt   string      `json:"created_at"`
+	UpdatedAt   string      `json:"updated_at"`
+	ClosedAt    string      `json:"closed_at"`
+	PullRequest *struct {
+		MergedAt string `json:"merged_at"`
+	} `json:"pull_request,omitempty"`
+}
+
+// RepoContributor is a contributor with their commit count on the default branch
+type RepoContributor struct {
+	ID            int64  `json:"id"`
+	Login         string `json:"login"`
+	AvatarURL     string `json:"avatar_url"`
+	HTMLURL       string `json:"html_url"`
+	Contributions int    `json:"contributions"`
+}
+
+// CommitCadence summarises how often a repository receives commits, measured
+// over its most recent commits
+type CommitCadence struct {
+	Sampled        int        `json:"sampled"`
+	CommitsPerWeek float64    `json:"commits_per_week"`
+	MedianGapHours float64    `json:"median_gap_hours"`
+	LastCommitAt   *time.Time `json:"last_commit_at,omitempty"`
+}
+
+// IssueRatio compares open and closed issues among the most recently
+// created ones, pull requests excluded. OpenRatio is open/(open+closed).
+type IssueRatio struct {
+	Open      int     `json:"open"`
+	Closed    int     `json:"closed"`
+	OpenRatio float64 `json:"open_ratio"`
+}
+
+// ContributorShare reports how concentrated commits are among contributors.
+// Shares are percentages of the commits of every listed contributor.
+type ContributorShare struct {
+	Contributors       int     `json:"contributors"`
+	TopContributor     string  `json:"top_contributor,omitempty"`
+	TopShare           float64 `json:"top_share"`
+	TopFiveShare       float64 `json:"top_five_share"`
+	TotalContributions int     `json:"total_contributions"`
+}
+
+// RepoMetrics are derived from the listings of a RepoAnalytics
+type RepoMetrics struct {
+	CommitCadence CommitCadence    `json:"commit_cadence"`
+	Issues        IssueRatio       `json:"issues"`
+	Contributors  ContributorShare `json:"contributors"`
+}
+
+// RepoAnalytics aggregates a repository with its recent commits, issues,
+// languages and contributors. Partial is set when one of the listings could
+// not be fetched and is left empty.
+type RepoAnalytics struct {
+	Repo         RepoDetails       `json:"repo"`
+	Commits      []RepoCommit      `json:"commits"`
+	Issues       []RepoIssue       `json:"issues"`
+	Languages    []LanguageShare   `json:"languages"`
+	Contributors []RepoContributor `json:"contributors"`
+	Metrics      RepoMetrics       `json:"metrics"`
+	Partial      bool              `json:"partial,omitempty"`
+}
+
+// HealthCheck is one item of a repository health checklist. Score is out of
+// MaxScore; Passed is set when the check earned full marks. Unscored checks
+// had nothing to judge and count towards neither score.
+type HealthCheck struct {
+	ID       string `json:"id"`
+	Label    string `json:"label"`
+	Passed   bool   `json:"passed"`
+	Unscored bool   `json:"unscored,omitempty"`
+	Score    int    `json:"score"`
+	MaxScore int    `json:"max_score"`
+	Detail   string `json:"detail"`
+}
+
+// RepoHealth is a repository's scored health checklist. Partial is set when
+// some of the data could not be fetched and its checks scored nothing.
+type RepoHealth struct {
+	Repo     string        `json:"repo"`
+	Score    int           `json:"score"`
+	MaxScore int           `json:"max_score"`
+	Checks   []HealthCheck `json:"checks"`
+	Partial  bool          `json:"partial,omitempty"`
+}
+
+// ContributorActivity is one contributor's commits and percentage share of
+// the commits in a window
+type ContributorActivity struct {
+	Login   string  `json:"login"`
+	Commits int     `json:"commits"`
+	Share   float64 `json:"share"`
+}
+
+// MaintainerChurn compares who committed in a window with who committed in
+// the window of the same length before it. ChurnRate is the fraction of the
+// previous window's contributors that did not commit again.
+type MaintainerChurn struct {
+	Previous  int      `json:"previous"`
+	Current   int      `json:"current"`
+	Retained  int      `json:"retained"`
+	Joined    []string `json:"joined"`
+	Left      []string `json:"left"`
+	ChurnRate float64  `json:"churn_rate"`
+}
+
+// ContributorConcentration reports how much of a repository's recent work
+// depends on few people. BusFactor is the fewest contributors authoring at
+// least half of the window's commits and Gini the inequality of their
+// commit counts (0 evenly shared, towards 1 concentrated). AllTime comes from
+// GitHub's contributor statistics, which GitHub computes in the background:
+// StatsPending is set while they are not ready. Truncated is set when the
+// commit listing stopped at the page cap, so the oldest commits (those of
+// the previous window first) are missing and Churn is unknown (null).
+type ContributorConcentration struct {
+	Repo             string                `json:"repo"`
+	WindowDays       int                   `json:"window_days"`
+	Since            time.Time             `json:"since"`
+	Commits          int                   `json:"commits"`
+	Contributors     []ContributorActivity `json:"contributors"`
+	BusFactor        int                   `json:"bus_factor"`
+	Gini             float64               `json:"gini"`
+	TopShare         float64               `json:"top_share"`
+	Churn            *MaintainerChurn      `json:"churn"`
+	AllTime          []ContributorActivity `json:"all_time,omitempty"`
+	AllTimeBusFactor int                   `json:"all_time_bus_factor,omitempty"`
+	StatsPending     bool                  `json:"stats_pending,omitempty"`
+	Truncated        bool                  `json:"truncated,omitempty"`
+}
+
+// VelocityWeek is one week of a repository's issue and pull request activity,
+// starting on Monday (UTC). Response and review times are bucketed by the
+// week an item was opened, close and merge times by the week it was closed;
+// medians are null for weeks without samples.
+type VelocityWeek struct {
+	WeekStart                time.Time `json:"week_start"`
+	IssuesOpened             int       `json:"issues_opened"`
+	IssuesClosed             int       `json:"issues_closed"`
+	PullsOpened              int       `json:"pulls_opened"`
+	PullsMerged              int       `json:"pulls_merged"`
+	PullsClosed              int       `json:"pulls_closed"`
+	Throughput               int       `json:"throughput"`
+	MedianFirstResponseHours *float64  `json:"median_first_response_hours"`
+	MedianCloseHours         *float64  `json:"median_close_hours"`
+	MedianMergeHours         *float64  `json:"median_merge_hours"`
+	MedianReviewHours        *float64  `json:"median_review_hours"`
+}
+
+// VelocitySummary aggregates a velocity series over the whole window.
+// Responded and Unanswered count the items opened in the window with and
+// without a response, Reviewed the sampled pull requests with a review and
+// StaleIssues the open issues not updated for StaleDays days.
+type VelocitySummary struct {
+	MedianFirstResponseHours *float64 `json:"median_first_response_hours"`
+	MedianCloseHours         *float64 `json:"median_close_hours"`
+	MedianMergeHours         *float64 `json:"median_merge_hours"`
+	MedianReviewHours        *float64 `json:"median_review_hours"`
+	Responded                int      `json:"responded"`
+	Unanswered               int      `json:"unanswered"`
+	Reviewed                 int      `json:"reviewed"`
+	StaleIssues              int      `json:"stale_issues"`
+	StaleDays                int      `json:"stale_days"`
+	WeeklyThroughput         float64  `json:"weekly_throughput"`
+}
+
+// RepoVelocity reports how quickly a repository responds to, reviews and
+// closes issues and pull requests, as a weekly series. Truncated is set when
+// a listing stopped at the page cap and Partial when part of the data could
+// not be fetched.
+type RepoVelocity struct {
+	Repo      string          `json:"repo"`
+	Weeks     int             `json:"weeks"`
+	Since     time.Time       `json:"since"`
+	Summary   VelocitySummary `json:"summary"`
+	Series    []VelocityWeek  `json:"series"`
+	Truncated bool            `json:"truncated,omitempty"`
+	Partial   bool            `json:"partial,omitempty"`
+}
@@ -28,6 +28,7 @@ type User struct {
 	LastLoginAt       time.Time `json:"last_login_at" db:"last_login_at"`
 	PreferredLanguage string    `json:"preferred_language" db:"preferred_language"`
 	Theme             string    `json:"theme" db:"theme"`
+	Timezone          string    `json:"timezone" db:"timezone"` // IANA name, empty for UTC
 }
 
 // UserWithToken includes sensitive token information (not for API responses)
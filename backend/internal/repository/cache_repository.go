@@ -0,0 +1,115 @@
+// Package repository provides persistent storage for cached GitHub payloads
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github-api/backend/internal/database"
+	"github-api/backend/internal/models"
+)
+
+// CacheRepository handles the second-level GitHub cache table
+type CacheRepository struct {
+	db *database.DB
+}
+
+// NewCacheRepository creates a new cache repository
+func NewCacheRepository(db *database.DB) *CacheRepository {
+	return &CacheRepository{db: db}
+}
+
+// Load retrieves a cached payload, returning nil when it does not exist
+func (r *CacheRepository) Load(ctx context.Context, kind, key string) (*models.CacheRecord, error) {
+	query := `
+		SELECT kind, cache_key, data, fetched_at, etag, last_modified
+		FROM github_cache
+		WHERE kind = $1 AND cache_key = $2
+	`
+
+	var record models.CacheRecord
+	err := r.db.QueryRowContext(ctx, query, kind, key).Scan(
+		&record.Kind, &record.Key, &record.Data, &record.FetchedAt,
+		&record.ETag, &record.LastModified,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cache record: %w", err)
+	}
+
+	return &record, nil
+}
+
+// LoadRecent retrieves the most recently fetched payloads of a kind
+func (r *CacheRepository) LoadRecent(ctx context.Context, kind string, since time.Time, limit int) ([]models.CacheRecord, error) {
+	query := `
+		SELECT kind, cache_key, data, fetched_at, etag, last_modified
+		FROM github_cache
+		WHERE kind = $1 AND fetched_at > $2
+		ORDER BY fetched_at DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, kind, since.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cache records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []models.CacheRecord
+	for rows.Next() {
+		var record models.CacheRecord
+		if err := rows.Scan(&record.Kind, &record.Key, &record.Data, &record.FetchedAt,
+			&record.ETag, &record.LastModified); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+// Save inserts or replaces a cached payload
+func (r *CacheRepository) Save(ctx context.Context, record *models.CacheRecord) error {
+	query := `
+		INSERT INTO github_cache (kind, cache_key, data, fetched_at, etag, last_modified)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (kind, cache_key) DO UPDATE SET
+			data = EXCLUDED.data,
+			fetched_at = EXCLUDED.fetched_at,
+			etag = EXCLUDED.etag,
+			last_modified = EXCLUDED.last_modified
+	`
+
+	_, err := r.db.ExecContext(ctx, query, record.Kind, record.Key, record.Data, record.FetchedAt.UTC(),
+		record.ETag, record.LastModified)
+	if err != nil {
+		return fmt.Errorf("failed to save cache record: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a single cached payload
+func (r *CacheRepository) Delete(ctx context.Context, kind, key string) error {
+	query := `DELETE FROM github_cache WHERE kind = $1 AND cache_key = $2`
+	_, err := r.db.ExecContext(ctx, query, kind, key)
+	return err
+}
+
+// DeletePrefix removes every cached payload of a kind whose key starts with prefix
+func (r *CacheRepository) DeletePrefix(ctx context.Context, kind, prefix string) error {
+	query := `DELETE FROM github_cache WHERE kind = $1 AND left(cache_key, length($2)) = $2`
+	_, err := r.db.ExecContext(ctx, query, kind, prefix)
+	return err
+}
+
+// Clear removes all cached payloads of a kind
+func (r *CacheRepository) Clear(ctx context.Context, kind string) error {
+	query := `DELETE FROM github_cache WHERE kind = $1`
+	_, err := r.db.ExecContext(ctx, query, kind)
+	return err
+}
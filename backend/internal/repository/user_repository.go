@@ -76,7 +76,7 @@ func (r *UserRepository) GetUserByGitHubID(ctx context.Context, githubID int64)
 		SELECT id, github_id, username, name, email, avatar_url, bio, location,
 			company, blog, twitter_username, public_repos, public_gists, followers,
 			following, access_token, refresh_token, token_expires_at, has_private_access,
-			created_at, updated_at, last_login_at
+			created_at, updated_at, last_login_at, timezone
 		FROM users WHERE github_id = $1
 	`
 
@@ -87,7 +87,7 @@ func (r *UserRepository) GetUserByGitHubID(ctx context.Context, githubID int64)
 		&user.TwitterUsername, &user.PublicRepos, &user.PublicGists,
 		&user.Followers, &user.Following, &user.AccessToken, &user.RefreshToken,
 		&user.TokenExpiresAt, &user.HasPrivateAccess, &user.CreatedAt,
-		&user.UpdatedAt, &user.LastLoginAt,
+		&user.UpdatedAt, &user.LastLoginAt, &user.Timezone,
 	)
 
 	if err == sql.ErrNoRows {
@@ -101,7 +101,7 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id int) (*models.User,
 	query := `
 		SELECT id, github_id, username, name, email, avatar_url, bio, location,
 			company, blog, twitter_username, public_repos, public_gists, followers,
-			following, has_private_access, created_at, updated_at, last_login_at
+			following, has_private_access, created_at, updated_at, last_login_at, timezone
 		FROM users WHERE id = $1
 	`
 
@@ -111,7 +111,7 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id int) (*models.User,
 		&user.AvatarURL, &user.Bio, &user.Location, &user.Company, &user.Blog,
 		&user.TwitterUsername, &user.PublicRepos, &user.PublicGists,
 		&user.Followers, &user.Following, &user.HasPrivateAccess,
-		&user.CreatedAt, &user.UpdatedAt, &user.LastLoginAt,
+		&user.CreatedAt, &user.UpdatedAt, &user.LastLoginAt, &user.Timezone,
 	)
 
 	if err == sql.ErrNoRows {
@@ -126,7 +126,7 @@ func (r *UserRepository) GetUserWithTokenByID(ctx context.Context, id int) (*mod
 		SELECT id, github_id, username, name, email, avatar_url, bio, location,
 			company, blog, twitter_username, public_repos, public_gists, followers,
 			following, access_token, refresh_token, token_expires_at, has_private_access,
-			created_at, updated_at, last_login_at
+			created_at, updated_at, last_login_at, timezone
 		FROM users WHERE id = $1
 	`
 
@@ -137,7 +137,7 @@ func (r *UserRepository) GetUserWithTokenByID(ctx context.Context, id int) (*mod
 		&user.TwitterUsername, &user.PublicRepos, &user.PublicGists,
 		&user.Followers, &user.Following, &user.AccessToken, &user.RefreshToken,
 		&user.TokenExpiresAt, &user.HasPrivateAccess, &user.CreatedAt,
-		&user.UpdatedAt, &user.LastLoginAt,
+		&user.UpdatedAt, &user.LastLoginAt, &user.Timezone,
 	)
 
 	if err == sql.ErrNoRows {
@@ -146,6 +146,13 @@ func (r *UserRepository) GetUserWithTokenByID(ctx context.Context, id int) (*mod
 	return user, err
 }
 
+// UpdateTimezone saves the IANA time zone a user's activity is shown in
+func (r *UserRepository) UpdateTimezone(ctx context.Context, userID int, timezone string) error {
+	query := `UPDATE users SET timezone = $1, updated_at = $2 WHERE id = $3`
+	_, err := r.db.ExecContext(ctx, query, timezone, time.Now(), userID)
+	return err
+}
+
 // CreateSession creates a new session
 func (r *UserRepository) CreateSession(ctx context.Context, session *models.Session) error {
 	query := `INSERT INTO sessions (id, user_id, expires_at) VALUES ($1, $2, $3)`
@@ -0,0 +1,229 @@
+// Package retry retries outbound HTTP requests that fail transiently
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github-api/backend/internal/models"
+)
+
+// Policy bounds how a failed request is retried
+type Policy struct {
+	MaxAttempts int           // including the first; 1 disables retries
+	MaxElapsed  time.Duration // no attempt starts after this much time
+	BaseDelay   time.Duration // backoff before the first retry
+	MaxDelay    time.Duration // backoff ceiling
+}
+
+// DefaultPolicy makes up to 3 attempts within 10 seconds, backing off from
+// 200ms to at most 2s
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts: 3,
+		MaxElapsed:  10 * time.Second,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    2 * time.Second,
+	}
+}
+
+// backoff returns the delay before retry number n (starting at 1), drawn
+// uniformly from [0, min(MaxDelay, BaseDelay*2^(n-1))] so that clients
+// failing together do not retry together
+func (p Policy) backoff(n int) time.Duration {
+	ceiling := p.MaxDelay
+	if shift := n - 1; shift < 30 && p.BaseDelay<<shift < ceiling {
+		ceiling = p.BaseDelay << shift
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling + 1)
+}
+
+type idempotentKey struct{}
+
+// WithIdempotent marks requests made with ctx as safe to send more than
+// once, for POSTs that have no side effects
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// Idempotent reports whether req may be replayed: its method is idempotent
+// per RFC 9110 or its context was marked with WithIdempotent
+func Idempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace,
+		http.MethodPut, http.MethodDelete:
+		return true
+	}
+	marked, _ := req.Context().Value(idempotentKey{}).(bool)
+	return marked
+}
+
+// Transport is an http.RoundTripper that retries idempotent requests failing
+// with 502, 503 or 504 or a dropped connection. A Retry-After header on the
+// failed response is honoured in place of the backoff; if it would overrun
+// the policy's time budget the failure is returned as is.
+type Transport struct {
+	name   string
+	policy Policy
+	base   http.RoundTripper
+	sleep  func(ctx context.Context, d time.Duration) error
+
+	retries   atomic.Int64
+	recovered atomic.Int64
+	exhausted atomic.Int64
+}
+
+// NewTransport wraps base (http.DefaultTransport when nil). name labels the
+// upstream in logs and stats.
+func NewTransport(name string, policy Policy, base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return &Transport{name: name, policy: policy, base: base, sleep: sleep}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !Idempotent(req) {
+		return t.base.RoundTrip(req)
+	}
+
+	start := time.Now()
+	attempt := req
+	for n := 1; ; n++ {
+		resp, err := t.base.RoundTrip(attempt)
+		if !transient(req.Context(), resp, err) {
+			if n > 1 {
+				t.recovered.Add(1)
+				log.Printf("✅ [Retry] %s %s %s succeeded after %d retries", t.name, req.Method, req.URL.Path, n-1)
+			}
+			return resp, err
+		}
+
+		delay, ok := retryAfter(resp, time.Now())
+		if !ok {
+			delay = t.policy.backoff(n)
+		}
+		if n >= t.policy.MaxAttempts || time.Since(start)+delay > t.policy.MaxElapsed {
+			t.exhausted.Add(1)
+			log.Printf("❌ [Retry] %s %s %s giving up after %d attempts: %s", t.name, req.Method, req.URL.Path, n, describe(resp, err))
+			return resp, err
+		}
+
+		next, rewound := replay(req)
+		if !rewound {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+
+		t.retries.Add(1)
+		log.Printf("🔁 [Retry] %s %s %s failed (%s), attempt %d/%d in %v",
+			t.name, req.Method, req.URL.Path, describe(resp, err), n+1, t.policy.MaxAttempts, delay.Round(time.Millisecond))
+		if err := t.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+		attempt = next
+	}
+}
+
+// Stats reports how many retries this transport has made
+func (t *Transport) Stats() models.RetryStats {
+	return models.RetryStats{
+		Upstream:  t.name,
+		Retries:   t.retries.Load(),
+		Recovered: t.recovered.Load(),
+		Exhausted: t.exhausted.Load(),
+	}
+}
+
+// transient reports whether a failed attempt is worth repeating
+func transient(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		var netErr net.Error
+		return errors.Is(err, syscall.ECONNRESET) ||
+			errors.Is(err, syscall.ECONNREFUSED) ||
+			errors.Is(err, io.ErrUnexpectedEOF) ||
+			errors.Is(err, io.EOF) ||
+			errors.As(err, &netErr) && netErr.Timeout()
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter reads a Retry-After header given in seconds or as an HTTP date
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// replay returns a copy of req with a fresh body, reporting whether the body
+// could be rewound
+func replay(req *http.Request) (*http.Request, bool) {
+	next := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return next, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	next.Body = body
+	return next, true
+}
+
+func describe(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,220 @@
+// Package retry provides tests for retrying transient HTTP failures
+package retry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first failures requests with status, then echoes the body
+func flakyServer(t *testing.T, failures int32, status int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			for name, values := range header {
+				w.Header()[name] = values
+			}
+			w.WriteHeader(status)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+// newTestTransport records the delays it would have slept instead of sleeping
+func newTestTransport(policy Policy) (*Transport, *[]time.Duration) {
+	var delays []time.Duration
+	transport := NewTransport("test", policy, nil)
+	transport.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+	return transport, &delays
+}
+
+func TestRetriesTransientStatus(t *testing.T) {
+	for _, status := range []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout} {
+		server, calls := flakyServer(t, 2, status, nil)
+		transport, _ := newTestTransport(DefaultPolicy())
+		client := &http.Client{Transport: transport}
+
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK || calls.Load() != 3 {
+			t.Errorf("%d: expected success on the third attempt, got %d after %d calls", status, resp.StatusCode, calls.Load())
+		}
+		if stats := transport.Stats(); stats.Retries != 2 || stats.Recovered != 1 || stats.Exhausted != 0 {
+			t.Errorf("%d: unexpected stats %+v", status, stats)
+		}
+	}
+}
+
+func TestGivesUpAfterMaxAttempts(t *testing.T) {
+	server, calls := flakyServer(t, 10, http.StatusServiceUnavailable, nil)
+	transport, _ := newTestTransport(DefaultPolicy())
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected the last failure to be returned, got %d", resp.StatusCode)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls.Load())
+	}
+	if stats := transport.Stats(); stats.Retries != 2 || stats.Exhausted != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestDoesNotRetryPermanentErrors(t *testing.T) {
+	server, calls := flakyServer(t, 1, http.StatusInternalServerError, nil)
+	transport, _ := newTestTransport(DefaultPolicy())
+	client := &http.Client{Transport: transport}
+
+	resp, _ := client.Get(server.URL)
+	resp.Body.Close()
+	if calls.Load() != 1 {
+		t.Errorf("A 500 should not be retried, got %d calls", calls.Load())
+	}
+}
+
+func TestPostOnlyRetriedWhenMarkedIdempotent(t *testing.T) {
+	server, calls := flakyServer(t, 1, http.StatusBadGateway, nil)
+	transport, _ := newTestTransport(DefaultPolicy())
+	client := &http.Client{Transport: transport}
+
+	resp, _ := client.Post(server.URL, "application/json", strings.NewReader(`{}`))
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway || calls.Load() != 1 {
+		t.Fatalf("A plain POST must not be replayed, got %d after %d calls", resp.StatusCode, calls.Load())
+	}
+
+	calls.Store(0)
+	req, _ := http.NewRequestWithContext(WithIdempotent(context.Background()), http.MethodPost, server.URL, strings.NewReader(`{"prompt":"hi"}`))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if calls.Load() != 2 || string(body) != `{"prompt":"hi"}` {
+		t.Errorf("Expected the body to be replayed on the retry, got %q after %d calls", body, calls.Load())
+	}
+}
+
+func TestHonoursRetryAfter(t *testing.T) {
+	server, _ := flakyServer(t, 1, http.StatusServiceUnavailable, http.Header{"Retry-After": {"3"}})
+	transport, delays := newTestTransport(DefaultPolicy())
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(*delays) != 1 || (*delays)[0] != 3*time.Second {
+		t.Errorf("Expected to wait the 3s GitHub asked for, got %v", *delays)
+	}
+}
+
+func TestRetryAfterBeyondBudgetFailsFast(t *testing.T) {
+	server, calls := flakyServer(t, 1, http.StatusServiceUnavailable, http.Header{"Retry-After": {"120"}})
+	transport, delays := newTestTransport(DefaultPolicy())
+	client := &http.Client{Transport: transport}
+
+	resp, _ := client.Get(server.URL)
+	resp.Body.Close()
+
+	if calls.Load() != 1 || len(*delays) != 0 {
+		t.Errorf("A wait past MaxElapsed should not be attempted, got %d calls and delays %v", calls.Load(), *delays)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected the 503 to be returned, got %d", resp.StatusCode)
+	}
+}
+
+func TestRetriesDroppedConnection(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+
+	transport, _ := newTestTransport(DefaultPolicy())
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected the reset connection to be retried: %v", err)
+	}
+	resp.Body.Close()
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestCancelledContextStopsRetrying(t *testing.T) {
+	server, calls := flakyServer(t, 10, http.StatusBadGateway, nil)
+	transport := NewTransport("test", Policy{MaxAttempts: 5, MaxElapsed: time.Minute, BaseDelay: time.Second, MaxDelay: time.Second}, nil)
+	client := &http.Client{Transport: transport}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+
+	start := time.Now()
+	if _, err := client.Do(req); err == nil {
+		t.Fatal("Expected the deadline to end the retries")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Backoff should stop when the context ends, took %v", elapsed)
+	}
+	if calls.Load() > 2 {
+		t.Errorf("Expected at most 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestBackoffIsBoundedAndJittered(t *testing.T) {
+	policy := Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: 400 * time.Millisecond}
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 50; i++ {
+		for n, ceiling := range []time.Duration{100, 200, 400, 400} {
+			delay := policy.backoff(n + 1)
+			if delay < 0 || delay > ceiling*time.Millisecond {
+				t.Fatalf("Retry %d: delay %v outside [0, %v]", n+1, delay, ceiling*time.Millisecond)
+			}
+			seen[delay] = true
+		}
+	}
+	if len(seen) < 10 {
+		t.Errorf("Expected jittered delays, got only %d distinct values", len(seen))
+	}
+}
@@ -0,0 +1,136 @@
+// Package service provides developer activity analysis
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github-api/backend/internal/models"
+)
+
+const (
+	// trendDays is the length of an activity trend and of its rolling window
+	trendDays = 30
+
+	// topActiveRepos bounds the most active repositories in an activity report
+	topActiveRepos = 10
+)
+
+// GetUserActivity describes when and where a user works from their recent
+// public events, reading days and hours in loc. It builds on the cached
+// events of the extended profile, so the events are only fetched again once
+// they expire and fail to revalidate, and like them users' own events are
+// read with the shared tokens so that the report never shows private ones.
+func (s *GitHubService) GetUserActivity(ctx context.Context, username string, useCache bool, loc *time.Location) (*models.UserActivity, error) {
+	if _, err := s.GetUserStatus(ctx, username, useCache); err != nil {
+		return nil, err
+	}
+
+	list, err := s.GetUserEvents(ctx, username, useCache)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := userActivity(list.Events, loc, timeNow())
+	activity.Username = username
+	activity.Truncated = list.Truncated
+	return activity, nil
+}
+
+// userActivity aggregates events into a weekday and hour heatmap, a breakdown
+// by kind, a daily trend ending today and the most active repositories
+func userActivity(events []models.GitHubEvent, loc *time.Location, now time.Time) *models.UserActivity {
+	activity := &models.UserActivity{TimeZone: loc.String(), Trend: []models.ActivityTrendDay{}}
+	perDay := make(map[string]int)
+	repos := make(map[string]*models.ActiveRepo)
+
+	for _, event := range events {
+		t, err := time.Parse(time.RFC3339, event.CreatedAt)
+		if err != nil {
+			continue
+		}
+		local := t.In(loc)
+		day := local.Format("2006-01-02")
+
+		activity.TotalEvents++
+		activity.Heatmap[local.Weekday()][local.Hour()]++
+		countEvent(&activity.Breakdown, event.Type)
+		perDay[day]++
+		if activity.Since == "" || day < activity.Since {
+			activity.Since = day
+		}
+
+		if event.Repo.Name == "" {
+			continue
+		}
+		repo, ok := repos[event.Repo.Name]
+		if !ok {
+			repo = &models.ActiveRepo{Name: event.Repo.Name}
+			repos[event.Repo.Name] = repo
+		}
+		repo.Events++
+		if day > repo.LastActive {
+			repo.LastActive = day
+		}
+	}
+
+	// Days are stepped on the calendar so DST changes do not skip or repeat one
+	today := now.In(loc)
+	date := func(daysAgo int) string {
+		return time.Date(today.Year(), today.Month(), today.Day()-daysAgo, 12, 0, 0, 0, loc).Format("2006-01-02")
+	}
+	rolling := func(daysAgo int) int {
+		total := 0
+		for i := daysAgo; i < daysAgo+trendDays; i++ {
+			total += perDay[date(i)]
+		}
+		return total
+	}
+	for daysAgo := trendDays - 1; daysAgo >= 0; daysAgo-- {
+		activity.Trend = append(activity.Trend, models.ActivityTrendDay{
+			Date:      date(daysAgo),
+			Events:    perDay[date(daysAgo)],
+			Rolling30: rolling(daysAgo),
+		})
+	}
+	if previous := rolling(trendDays); previous > 0 {
+		current := rolling(0)
+		activity.TrendChange = math.Round(float64(current-previous)*1000/float64(previous)) / 10
+	}
+
+	activity.TopRepos = make([]models.ActiveRepo, 0, len(repos))
+	for _, repo := range repos {
+		activity.TopRepos = append(activity.TopRepos, *repo)
+	}
+	sort.Slice(activity.TopRepos, func(i, j int) bool {
+		a, b := activity.TopRepos[i], activity.TopRepos[j]
+		if a.Events != b.Events {
+			return a.Events > b.Events
+		}
+		return a.Name < b.Name
+	})
+	if len(activity.TopRepos) > topActiveRepos {
+		activity.TopRepos = activity.TopRepos[:topActiveRepos]
+	}
+	return activity
+}
+
+// countEvent adds an event to the breakdown for its type
+func countEvent(breakdown *models.ActivityBreakdown, eventType string) {
+	switch eventType {
+	case "PushEvent":
+		breakdown.Push++
+	case "PullRequestEvent":
+		breakdown.PullRequest++
+	case "PullRequestReviewEvent", "PullRequestReviewCommentEvent", "PullRequestReviewThreadEvent":
+		breakdown.Review++
+	case "IssuesEvent", "IssueCommentEvent":
+		breakdown.Issue++
+	case "ReleaseEvent":
+		breakdown.Release++
+	default:
+		breakdown.Other++
+	}
+}
@@ -0,0 +1,69 @@
+// Package service provides tests for developer activity analysis
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github-api/backend/internal/models"
+)
+
+func TestUserActivity(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := func(eventType, createdAt, repo string) models.GitHubEvent {
+		return models.GitHubEvent{Type: eventType, CreatedAt: createdAt, Repo: models.EventRepo{Name: repo}}
+	}
+	events := []models.GitHubEvent{
+		event("PushEvent", "2024-05-31T03:00:00Z", "a/x"),
+		event("PushEvent", "2024-05-30T20:00:00Z", "a/x"),
+		event("PullRequestReviewEvent", "2024-05-20T10:00:00Z", "b/y"),
+		event("IssueCommentEvent", "2024-04-25T10:00:00Z", "b/y"),
+		event("WatchEvent", "2024-04-10T10:00:00Z", "c/z"),
+	}
+
+	activity := userActivity(events, loc, time.Date(2024, 5, 31, 12, 0, 0, 0, time.UTC))
+
+	if activity.TotalEvents != 5 || activity.Since != "2024-04-10" || activity.TimeZone != "Asia/Kolkata" {
+		t.Errorf("Unexpected totals %+v", activity)
+	}
+	// Friday 08:30 and Friday 01:30 (Thursday in UTC), Monday 15:30 in Kolkata
+	if activity.Heatmap[time.Friday][8] != 1 || activity.Heatmap[time.Friday][1] != 1 || activity.Heatmap[time.Monday][15] != 1 {
+		t.Errorf("Events should be placed by local weekday and hour, got %v", activity.Heatmap)
+	}
+	want := models.ActivityBreakdown{Push: 2, Review: 1, Issue: 1, Other: 1}
+	if activity.Breakdown != want {
+		t.Errorf("Expected breakdown %+v, got %+v", want, activity.Breakdown)
+	}
+
+	if len(activity.Trend) != 30 || activity.Trend[0].Date != "2024-05-02" {
+		t.Fatalf("Expected 30 days from 2024-05-02, got %d starting %v", len(activity.Trend), activity.Trend)
+	}
+	if last := activity.Trend[29]; last.Date != "2024-05-31" || last.Events != 2 || last.Rolling30 != 3 {
+		t.Errorf("Unexpected last trend day %+v", last)
+	}
+	if activity.TrendChange != 50 {
+		t.Errorf("Expected 3 events against 2 to be a 50%% change, got %v", activity.TrendChange)
+	}
+
+	if len(activity.TopRepos) != 3 || activity.TopRepos[0].Name != "a/x" || activity.TopRepos[0].LastActive != "2024-05-31" || activity.TopRepos[1].Name != "b/y" {
+		t.Errorf("Unexpected top repositories %+v", activity.TopRepos)
+	}
+}
+
+func TestUserActivityIgnoresSessionToken(t *testing.T) {
+	svc, _ := newFixture(t, map[string]any{
+		"/users/octocat":        `{"login":"octocat"}`,
+		"/users/octocat/events": `[{"type":"PushEvent","created_at":"2024-05-01T00:00:00Z","repo":{"name":"octocat/hello"}}]`,
+	})
+
+	activity, err := svc.GetUserActivity(sessionContext(), "octocat", true, time.UTC)
+	if err != nil {
+		t.Fatalf("GetUserActivity failed: %v", err)
+	}
+	if len(activity.TopRepos) != 1 || activity.TopRepos[0].Name != "octocat/hello" {
+		t.Errorf("Unexpected top repositories %+v", activity.TopRepos)
+	}
+}
@@ -0,0 +1,330 @@
+// Package service provides relationship analysis for user comparisons
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github-api/backend/internal/github"
+	"github-api/backend/internal/models"
+)
+
+// overlapItems bounds the items listed in an overlap
+const overlapItems = 50
+
+// FetchUserConnections fetches the accounts a user follows and is followed
+// by and the repositories they starred, each up to MaxConnectionPages pages,
+// and caches them. Users' own connections are fetched with the shared
+// tokens, and private repositories a session token can see are left out of
+// anyone's stars. Concurrent calls for the same user share a single fetch.
+func (s *GitHubService) FetchUserConnections(ctx context.Context, username string) (models.UserConnections, error) {
+	key := strings.ToLower(username)
+	return s.connectionFlight.DoContext(ctx, key, func(ctx context.Context) (models.UserConnections, error) {
+		ctx = publicContext(ctx, username)
+		base := "/users/" + url.PathEscape(username)
+		maxPages := s.config.MaxConnectionPages
+
+		type account struct {
+			Login string `json:"login"`
+		}
+		type starred struct {
+			FullName string `json:"full_name"`
+			Private  bool   `json:"private"`
+		}
+		var followers, following []account
+		var stars []starred
+		var truncated [3]bool
+		errs := fanOut(map[string]func() error{
+			"followers": func() (err error) {
+				followers, truncated[0], _, err = fetchAll[account](ctx, s.client, base+"/followers?per_page=100", github.Validators{}, maxPages)
+				return err
+			},
+			"following": func() (err error) {
+				following, truncated[1], _, err = fetchAll[account](ctx, s.client, base+"/following?per_page=100", github.Validators{}, maxPages)
+				return err
+			},
+			"starred": func() (err error) {
+				stars, truncated[2], _, err = fetchAll[starred](ctx, s.client, base+"/starred?per_page=100", github.Validators{}, maxPages)
+				return err
+			},
+		})
+		if err := ctx.Err(); err != nil {
+			return models.UserConnections{}, err
+		}
+		for _, name := range []string{"followers", "following", "starred"} {
+			if err := errs[name]; err != nil {
+				if errors.Is(err, github.ErrNotFound) {
+					return models.UserConnections{}, ErrUserNotFound
+				}
+				return models.UserConnections{}, fmt.Errorf("failed to fetch %s of %s: %w", name, username, err)
+			}
+		}
+
+		connections := models.UserConnections{
+			Followers: make([]string, 0, len(followers)),
+			Following: make([]string, 0, len(following)),
+			Starred:   make([]string, 0, len(stars)),
+			Truncated: truncated[0] || truncated[1] || truncated[2],
+		}
+		for _, a := range followers {
+			connections.Followers = append(connections.Followers, a.Login)
+		}
+		for _, a := range following {
+			connections.Following = append(connections.Following, a.Login)
+		}
+		for _, repo := range stars {
+			if !repo.Private {
+				connections.Starred = append(connections.Starred, repo.FullName)
+			}
+		}
+
+		s.connectionCache.Set(key, connections)
+		return connections, nil
+	})
+}
+
+// GetUserConnections gets a user's followers, following and stars with caching
+func (s *GitHubService) GetUserConnections(ctx context.Context, username string, useCache bool) (models.UserConnections, error) {
+	if useCache {
+		if connections, found := s.connectionCache.Get(strings.ToLower(username)); found {
+			return connections, nil
+		}
+	}
+	return s.FetchUserConnections(ctx, username)
+}
+
+// comparedProfile is what a comparison reads for one user, as sets keyed by
+// lowercase name with the name as GitHub spells it
+type comparedProfile struct {
+	login     string
+	followers map[string]string
+	following map[string]string
+	starred   map[string]string
+	repos     map[string]string
+	languages map[string]int
+	truncated bool
+
+	// eventsErr records that events were unavailable, which only narrows repos
+	eventsErr error
+}
+
+// CompareUsers relates two or more users through shared followers and
+// following, mutual follows, repositories they both contributed to (owned
+// sources, or pushed to, opened pull requests in or reviewed according to
+// their events), starred repositories and the primary languages of their
+// repositories. Every pair is compared and the overlap of all users is
+// reported as common ground. Everything comes from the shared per-user
+// caches, so a signed-in user's private events never reach the overlaps.
+func (s *GitHubService) CompareUsers(ctx context.Context, usernames []string, useCache bool) (*models.UserComparison, error) {
+	profiles := make([]*comparedProfile, len(usernames))
+	errs := make([]error, len(usernames))
+	var wg sync.WaitGroup
+	for i, username := range usernames {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			profiles[i], errs[i] = s.comparedProfile(ctx, username, useCache)
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", usernames[i], err)
+		}
+	}
+
+	comparison := &models.UserComparison{
+		Users: make([]models.ComparedUser, 0, len(profiles)),
+		Pairs: []models.ComparisonPair{},
+	}
+	for i, p := range profiles {
+		if p.eventsErr != nil {
+			log.Printf("⚠️ [Compare] events unavailable for %s: %v", p.login, p.eventsErr)
+			comparison.Partial = true
+		}
+		comparison.Users = append(comparison.Users, models.ComparedUser{
+			Login:            p.login,
+			Followers:        len(p.followers),
+			Following:        len(p.following),
+			Starred:          len(p.starred),
+			ContributedRepos: len(p.repos),
+			Truncated:        p.truncated,
+		})
+		for _, q := range profiles[i+1:] {
+			comparison.Pairs = append(comparison.Pairs, comparePair(p, q))
+		}
+	}
+
+	common := func(set func(*comparedProfile) map[string]string) models.Overlap {
+		shared := set(profiles[0])
+		for _, p := range profiles[1:] {
+			shared = intersect(shared, set(p))
+		}
+		return overlap(shared)
+	}
+	comparison.Common = models.CommonGround{
+		Followers: common(func(p *comparedProfile) map[string]string { return p.followers }),
+		Following: common(func(p *comparedProfile) map[string]string { return p.following }),
+		Repos:     common(func(p *comparedProfile) map[string]string { return p.repos }),
+		Stars:     common(func(p *comparedProfile) map[string]string { return p.starred }),
+	}
+	return comparison, nil
+}
+
+// comparedProfile gathers a user's connections, contributed repositories and
+// languages
+func (s *GitHubService) comparedProfile(ctx context.Context, username string, useCache bool) (*comparedProfile, error) {
+	var connections models.UserConnections
+	var repos models.RepoList
+	var events models.EventList
+	errs := fanOut(map[string]func() error{
+		"connections": func() (err error) {
+			connections, err = s.GetUserConnections(ctx, username, useCache)
+			return err
+		},
+		"repos": func() (err error) {
+			repos, err = s.GetUserRepos(ctx, username, useCache)
+			return err
+		},
+		"events": func() (err error) {
+			events, err = s.GetUserEvents(ctx, username, useCache)
+			return err
+		},
+	})
+	if err := errs["connections"]; err != nil {
+		return nil, err
+	}
+	if err := errs["repos"]; err != nil {
+		if errors.Is(err, github.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	p := &comparedProfile{
+		login:     username,
+		followers: nameSet(connections.Followers),
+		following: nameSet(connections.Following),
+		starred:   nameSet(connections.Starred),
+		repos:     make(map[string]string),
+		languages: make(map[string]int),
+		truncated: connections.Truncated || repos.Truncated || events.Truncated,
+		eventsErr: errs["events"],
+	}
+	for _, repo := range repos.Repos {
+		if repo.Fork {
+			continue
+		}
+		if repo.FullName != "" {
+			p.repos[strings.ToLower(repo.FullName)] = repo.FullName
+		}
+		if repo.Language != "" {
+			p.languages[repo.Language]++
+		}
+	}
+	for _, event := range events.Events {
+		switch event.Type {
+		case "PushEvent", "PullRequestEvent", "PullRequestReviewEvent", "PullRequestReviewCommentEvent":
+			if event.Repo.Name != "" {
+				p.repos[strings.ToLower(event.Repo.Name)] = event.Repo.Name
+			}
+		}
+	}
+	return p, nil
+}
+
+// comparePair relates two users
+func comparePair(a, b *comparedProfile) models.ComparisonPair {
+	aLogin, bLogin := strings.ToLower(a.login), strings.ToLower(b.login)
+	pair := models.ComparisonPair{
+		Users:              [2]string{a.login, b.login},
+		FirstFollowsSecond: hasKey(a.following, bLogin) || hasKey(b.followers, aLogin),
+		SecondFollowsFirst: hasKey(b.following, aLogin) || hasKey(a.followers, bLogin),
+		SharedFollowers:    overlap(intersect(a.followers, b.followers)),
+		SharedFollowing:    overlap(intersect(a.following, b.following)),
+		SharedRepos:        overlap(intersect(a.repos, b.repos)),
+		SharedStars:        overlap(intersect(a.starred, b.starred)),
+		StarSimilarity:     jaccard(a.starred, b.starred),
+		LanguageSimilarity: weightedJaccard(a.languages, b.languages),
+	}
+	pair.MutualFollow = pair.FirstFollowsSecond && pair.SecondFollowsFirst
+	return pair
+}
+
+// nameSet indexes names by their lowercase form
+func nameSet(names []string) map[string]string {
+	set := make(map[string]string, len(names))
+	for _, name := range names {
+		set[strings.ToLower(name)] = name
+	}
+	return set
+}
+
+func hasKey(set map[string]string, key string) bool {
+	_, ok := set[key]
+	return ok
+}
+
+// intersect returns the names present in both sets, spelled as in a
+func intersect(a, b map[string]string) map[string]string {
+	shared := make(map[string]string)
+	for key, name := range a {
+		if hasKey(b, key) {
+			shared[key] = name
+		}
+	}
+	return shared
+}
+
+// overlap counts a set and lists its first items alphabetically
+func overlap(set map[string]string) models.Overlap {
+	items := make([]string, 0, len(set))
+	for _, name := range set {
+		items = append(items, name)
+	}
+	sort.Slice(items, func(i, j int) bool { return strings.ToLower(items[i]) < strings.ToLower(items[j]) })
+	if len(items) > overlapItems {
+		items = items[:overlapItems]
+	}
+	return models.Overlap{Count: len(set), Items: items}
+}
+
+// jaccard is the size of the intersection of two sets over their union
+func jaccard(a, b map[string]string) float64 {
+	shared := len(intersect(a, b))
+	union := len(a) + len(b) - shared
+	if union == 0 {
+		return 0
+	}
+	return math.Round(float64(shared)/float64(union)*1000) / 1000
+}
+
+// weightedJaccard compares two multisets as the sum of the smaller counts
+// over the sum of the larger ones
+func weightedJaccard(a, b map[string]int) float64 {
+	var minSum, maxSum int
+	for key, count := range a {
+		minSum += min(count, b[key])
+		maxSum += max(count, b[key])
+	}
+	for key, count := range b {
+		if _, ok := a[key]; !ok {
+			maxSum += count
+		}
+	}
+	if maxSum == 0 {
+		return 0
+	}
+	return math.Round(float64(minSum)/float64(maxSum)*1000) / 1000
+}
@@ -0,0 +1,71 @@
+// Package service provides tests for user comparisons
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestCompareUsers(t *testing.T) {
+	svc, fx := newFixture(t, map[string]any{
+		// The listing never ends, so only the cap stops it
+		"/users/alice/followers": func(w http.ResponseWriter, r *http.Request) {
+			nextPage(w, r)
+			w.Write([]byte(`[{"login":"carol"},{"login":"dave"}]`))
+		},
+		"/users/alice/following": `[{"login":"bob"},{"login":"erin"}]`,
+		"/users/alice/starred":   `[{"full_name":"x/a"},{"full_name":"x/b"},{"full_name":"acme/secret","private":true}]`,
+		"/users/alice/repos": `[
+			{"full_name":"alice/app","language":"Go"},
+			{"full_name":"alice/site","language":"JavaScript"},
+			{"full_name":"alice/fork","language":"Go","fork":true}
+		]`,
+		"/users/alice/events":  `[{"type":"PushEvent","created_at":"2024-05-01T00:00:00Z","repo":{"name":"bob/lib"}}]`,
+		"/users/bob/followers": `[{"login":"Alice"},{"login":"dave"}]`,
+		"/users/bob/following": `[{"login":"erin"},{"login":"frank"}]`,
+		"/users/bob/starred":   `[{"full_name":"x/b"},{"full_name":"x/c"},{"full_name":"acme/secret","private":true}]`,
+		"/users/bob/repos":     `[{"full_name":"bob/lib","language":"Go"}]`,
+		"/users/bob/events":    `[]`,
+	})
+	svc.config.MaxConnectionPages = 1
+
+	comparison, err := svc.CompareUsers(sessionContext(), []string{"alice", "bob"}, true)
+	if err != nil {
+		t.Fatalf("CompareUsers failed: %v", err)
+	}
+	if len(comparison.Users) != 2 || !comparison.Users[0].Truncated || comparison.Users[0].Followers != 2 || comparison.Users[1].Truncated {
+		t.Errorf("Expected alice's followers to stop at the page cap, got %+v", comparison.Users)
+	}
+	if len(comparison.Pairs) != 1 {
+		t.Fatalf("Expected one pair, got %d", len(comparison.Pairs))
+	}
+
+	pair := comparison.Pairs[0]
+	if !pair.FirstFollowsSecond || pair.SecondFollowsFirst || pair.MutualFollow {
+		t.Errorf("Expected alice to follow bob one way, got %+v", pair)
+	}
+	if pair.SharedFollowers.Count != 1 || pair.SharedFollowers.Items[0] != "dave" || pair.SharedFollowing.Items[0] != "erin" {
+		t.Errorf("Unexpected shared followers %+v and following %+v", pair.SharedFollowers, pair.SharedFollowing)
+	}
+	if pair.SharedRepos.Count != 1 || pair.SharedRepos.Items[0] != "bob/lib" {
+		t.Errorf("Expected bob/lib as a shared repository, got %+v", pair.SharedRepos)
+	}
+	if pair.SharedStars.Count != 1 || pair.StarSimilarity != 0.333 || pair.LanguageSimilarity != 0.5 {
+		t.Errorf("Expected star similarity 0.333 and language similarity 0.5, got %+v", pair)
+	}
+	if comparison.Common.Stars.Count != 1 || comparison.Common.Repos.Items[0] != "bob/lib" {
+		t.Errorf("Unexpected common ground %+v", comparison.Common)
+	}
+	if _, found := svc.connectionCache.Get("alice"); !found {
+		t.Error("Connections should be cached per user")
+	}
+	if auth := fx.authorization("/users/bob/starred"); auth != "Bearer user-token" {
+		t.Errorf("Other users' connections should be read with the session token, got %q", auth)
+	}
+
+	if _, err := svc.CompareUsers(context.Background(), []string{"alice", "ghost"}, true); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound for an unknown user, got %v", err)
+	}
+}
@@ -2,106 +2,327 @@
 package service
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"log"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github-api/backend/internal/cache"
 	"github-api/backend/internal/config"
+	"github-api/backend/internal/github"
 	"github-api/backend/internal/models"
 )
 
+// ErrUserNotFound is returned when GitHub reports that a user does not exist
+var ErrUserNotFound = errors.New("GitHub API returned status: 404")
+
 // Time helpers for streak calculation
 var timeNow = time.Now
 var timeParse = time.Parse
 
 // GitHubService handles GitHub API operations
 type GitHubService struct {
-	cache      *cache.Cache
-	httpClient *http.Client
-	config     *config.Config
+	cache      *cache.Tiered[models.GitHubUser]
+	repoCache  *cache.Tiered[models.RepoList]
+	eventCache *cache.Tiered[models.EventList]
+
+	// Contribution calendars from the GraphQL API
+	calendarCache *cache.Tiered[models.ContributionCalendar]
+
+	// Bytes per language keyed by "owner/repo"
+	languageCache *cache.Tiered[map[string]int64]
+
+	// Aggregated repository analytics and health keyed by lowercase "owner/repo"
+	analyticsCache *cache.Tiered[models.RepoAnalytics]
+	healthCache    *cache.Tiered[models.RepoHealth]
+
+	// Contributor concentration and velocity keyed by lowercase
+	// "owner/repo@days" and "owner/repo@weeks"
+	concentrationCache *cache.Tiered[models.ContributorConcentration]
+	velocityCache      *cache.Tiered[models.RepoVelocity]
+
+	// Collaboration graphs and followers, following and stars keyed by
+	// lowercase username
+	networkCache    *cache.Tiered[models.UserNetwork]
+	connectionCache *cache.Tiered[models.UserConnections]
+
+	// Short-lived record of usernames GitHub reported as missing
+	missCache *cache.Tiered[struct{}]
+
+	// In-flight request coalescing per upstream endpoint
+	userFlight          cache.Group[string, *models.GitHubUser]
+	repoFlight          cache.Group[string, models.RepoList]
+	eventFlight         cache.Group[string, models.EventList]
+	calendarFlight      cache.Group[string, models.ContributionCalendar]
+	languageFlight      cache.Group[string, map[string]int64]
+	analyticsFlight     cache.Group[string, models.RepoAnalytics]
+	healthFlight        cache.Group[string, models.RepoHealth]
+	concentrationFlight cache.Group[string, models.ContributorConcentration]
+	velocityFlight      cache.Group[string, models.RepoVelocity]
+	networkFlight       cache.Group[string, models.UserNetwork]
+	connectionFlight    cache.Group[string, models.UserConnections]
+
+	client *github.Client
+	config *config.Config
 }
 
 // NewGitHubService creates a new GitHub service
-func NewGitHubService(cfg *config.Config, c *cache.Cache) *GitHubService {
+func NewGitHubService(cfg *config.Config, c *cache.Cache[string, models.GitHubUser]) *GitHubService {
+	// Repos and events are never served stale, but expired entries are kept
+	// for the stale window so they can be revalidated with their ETag
+	repos := cache.New[string, models.RepoList](cfg.MaxCacheSize, cfg.CacheTTL)
+	repos.SetStaleTTL(cfg.CacheStaleTTL)
+	events := cache.New[string, models.EventList](cfg.MaxCacheSize, cfg.CacheTTL)
+	events.SetStaleTTL(cfg.CacheStaleTTL)
+	languages := cache.New[string, map[string]int64](cfg.MaxCacheSize, cfg.CacheTTL)
+	languages.SetStaleTTL(cfg.CacheStaleTTL)
+
 	return &GitHubService{
-		cache:  c,
-		config: cfg,
-		httpClient: &http.Client{
-			Timeout: cfg.Timeout,
-		},
+		cache:              cache.NewTiered(c, "user"),
+		repoCache:          cache.NewTiered(repos, "repos"),
+		eventCache:         cache.NewTiered(events, "events"),
+		calendarCache:      cache.NewTiered(cache.New[string, models.ContributionCalendar](cfg.MaxCacheSize, cfg.CacheTTL), "calendar"),
+		languageCache:      cache.NewTiered(languages, "languages"),
+		analyticsCache:     cache.NewTiered(cache.New[string, models.RepoAnalytics](cfg.MaxCacheSize, cfg.CacheTTL), "analytics"),
+		healthCache:        cache.NewTiered(cache.New[string, models.RepoHealth](cfg.MaxCacheSize, cfg.CacheTTL), "health"),
+		concentrationCache: cache.NewTiered(cache.New[string, models.ContributorConcentration](cfg.MaxCacheSize, cfg.CacheTTL), "concentration"),
+		velocityCache:      cache.NewTiered(cache.New[string, models.RepoVelocity](cfg.MaxCacheSize, cfg.CacheTTL), "velocity"),
+		networkCache:       cache.NewTiered(cache.New[string, models.UserNetwork](cfg.MaxCacheSize, cfg.CacheTTL), "network"),
+		connectionCache:    cache.NewTiered(cache.New[string, models.UserConnections](cfg.MaxCacheSize, cfg.CacheTTL), "connections"),
+		missCache:          cache.NewTiered(cache.New[string, struct{}](cfg.MaxCacheSize, cfg.NegativeCacheTTL), "negative"),
+		client:             github.NewPoolClient(cfg.GitHubAPIURL, sharedTokens(cfg), cfg.Timeout),
+		config:             cfg,
 	}
 }
 
-// setAuthHeaders adds authentication headers to request
-func (s *GitHubService) setAuthHeaders(req *http.Request) {
-	req.Header.Set("User-Agent", "DevScope-API")
-	// Only add authorization if token is valid (not empty or placeholder)
-	if s.config.GitHubToken != "" && s.config.GitHubToken != "YOUR_FINE_GRAINED_TOKEN_HERE" {
-		req.Header.Set("Authorization", "Bearer "+s.config.GitHubToken)
+// sharedTokens returns the configured server tokens (GITHUB_TOKENS followed
+// by GITHUB_TOKEN), ignoring the .env placeholder
+func sharedTokens(cfg *config.Config) []string {
+	candidates := append([]string{}, cfg.GitHubTokens...)
+	candidates = append(candidates, cfg.GitHubToken)
+
+	var tokens []string
+	for _, token := range candidates {
+		if token != "" && token != "YOUR_FINE_GRAINED_TOKEN_HERE" {
+			tokens = append(tokens, token)
+		}
 	}
+	return tokens
+}
+
+// GitHub returns the client used for upstream requests
+func (s *GitHubService) GitHub() *github.Client {
+	return s.client
 }
 
-// FetchUser fetches GitHub user information from API
-func (s *GitHubService) FetchUser(username string) (*models.GitHubUser, error) {
-	url := s.config.GitHubAPIURL + username
+// SetCacheStore attaches a persistent second-level store to every cache tier
+func (s *GitHubService) SetCacheStore(store cache.Store) {
+	s.cache.SetStore(store)
+	s.repoCache.SetStore(store)
+	s.eventCache.SetStore(store)
+	s.calendarCache.SetStore(store)
+	s.languageCache.SetStore(store)
+	s.analyticsCache.SetStore(store)
+	s.healthCache.SetStore(store)
+	s.concentrationCache.SetStore(store)
+	s.velocityCache.SetStore(store)
+	s.networkCache.SetStore(store)
+	s.connectionCache.SetStore(store)
+}
+
+// WarmCache loads recently fetched payloads from the persistent store into memory
+func (s *GitHubService) WarmCache(ctx context.Context) (int, error) {
+	limit := s.config.MaxCacheSize
+	total := 0
+
+	users, err := s.cache.Warm(ctx, limit)
+	if err != nil {
+		return total, fmt.Errorf("failed to warm user cache: %w", err)
+	}
+	total += users
+
+	repos, err := s.repoCache.Warm(ctx, limit)
+	if err != nil {
+		return total, fmt.Errorf("failed to warm repo cache: %w", err)
+	}
+	total += repos
 
-	req, err := http.NewRequest("GET", url, nil)
+	events, err := s.eventCache.Warm(ctx, limit)
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %v", err)
+		return total, fmt.Errorf("failed to warm event cache: %w", err)
 	}
+	total += events
 
-	s.setAuthHeaders(req)
+	calendars, err := s.calendarCache.Warm(ctx, limit)
+	if err != nil {
+		return total, fmt.Errorf("failed to warm calendar cache: %w", err)
+	}
+	total += calendars
 
-	resp, err := s.httpClient.Do(req)
+	languages, err := s.languageCache.Warm(ctx, limit)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %v", err)
+		return total, fmt.Errorf("failed to warm language cache: %w", err)
 	}
-	defer resp.Body.Close()
+	total += languages
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GitHub API returned status: %d", resp.StatusCode)
+	analytics, err := s.analyticsCache.Warm(ctx, limit)
+	if err != nil {
+		return total, fmt.Errorf("failed to warm analytics cache: %w", err)
 	}
+	total += analytics
 
-	body, err := io.ReadAll(resp.Body)
+	health, err := s.healthCache.Warm(ctx, limit)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response: %v", err)
+		return total, fmt.Errorf("failed to warm health cache: %w", err)
 	}
+	total += health
 
+	concentration, err := s.concentrationCache.Warm(ctx, limit)
+	if err != nil {
+		return total, fmt.Errorf("failed to warm concentration cache: %w", err)
+	}
+	total += concentration
+
+	velocity, err := s.velocityCache.Warm(ctx, limit)
+	if err != nil {
+		return total, fmt.Errorf("failed to warm velocity cache: %w", err)
+	}
+	total += velocity
+
+	networks, err := s.networkCache.Warm(ctx, limit)
+	if err != nil {
+		return total, fmt.Errorf("failed to warm network cache: %w", err)
+	}
+	total += networks
+
+	connections, err := s.connectionCache.Warm(ctx, limit)
+	if err != nil {
+		return total, fmt.Errorf("failed to warm connection cache: %w", err)
+	}
+	total += connections
+
+	return total, nil
+}
+
+// publicContext returns ctx for looking up login's data for the shared
+// caches. GitHub only adds private data when users look themselves up, so
+// the session token is dropped for the signed-in user's own account (or a
+// token whose account is unknown) and kept for everyone else's.
+func publicContext(ctx context.Context, login string) context.Context {
+	if github.TokenFromContext(ctx) == "" {
+		return ctx
+	}
+	if owner := github.TokenLogin(ctx); owner == "" || strings.EqualFold(owner, login) {
+		return github.ContextWithoutToken(ctx)
+	}
+	return ctx
+}
+
+// FetchUser fetches GitHub user information from API and caches it,
+// revalidating any cached copy with a conditional request. The profile is
+// shared between viewers, so users looking themselves up get it with the
+// shared tokens: their own would add their private counts and plan.
+// Concurrent calls for the same username share a single upstream request,
+// cancelled once every caller waiting on it has given up.
+func (s *GitHubService) FetchUser(ctx context.Context, username string) (*models.GitHubUser, error) {
+	key := strings.ToLower(username)
+	return s.userFlight.DoContext(ctx, key, func(ctx context.Context) (*models.GitHubUser, error) {
+		ctx = publicContext(ctx, username)
+		user, err := revalidate(s.cache, key, func(v github.Validators) (models.GitHubUser, github.Validators, error) {
+			return s.fetchUser(ctx, username, v)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &user, nil
+	})
+}
+
+func (s *GitHubService) fetchUser(ctx context.Context, username string, v github.Validators) (models.GitHubUser, github.Validators, error) {
 	var user models.GitHubUser
-	if err := json.Unmarshal(body, &user); err != nil {
-		return nil, fmt.Errorf("error parsing JSON: %v", err)
+	resp, notModified, err := s.client.GetConditional(ctx, "/users/"+url.PathEscape(username), v, &user)
+	switch {
+	case errors.Is(err, github.ErrNotFound):
+		return user, github.Validators{}, ErrUserNotFound
+	case err != nil:
+		return user, github.Validators{}, err
+	case notModified:
+		return user, v, github.ErrNotModified
+	}
+	return user, github.ValidatorsFrom(resp), nil
+}
+
+// revalidate fetches a cacheable resource and stores it in tier. The
+// validators of any cached copy are passed to fetch so that an unchanged
+// resource costs a 304 (free of rate limit) instead of a full response;
+// fetch signals that with github.ErrNotModified and the cached copy's TTL
+// is restarted without decoding it again.
+func revalidate[V any](tier *cache.Tiered[V], key string, fetch func(github.Validators) (V, github.Validators, error)) (V, error) {
+	var validators github.Validators
+	if etag, lastModified, ok := tier.Revalidate(key); ok {
+		validators = github.Validators{ETag: etag, LastModified: lastModified}
+	}
+
+	value, fresh, err := fetch(validators)
+	if errors.Is(err, github.ErrNotModified) {
+		if cached, ok := tier.Touch(key); ok {
+			return cached, nil
+		}
+		// The cached copy was evicted meanwhile; fetch it in full
+		value, fresh, err = fetch(github.Validators{})
+	}
+	if err != nil {
+		return value, err
 	}
 
-	return &user, nil
+	tier.SetWithValidators(key, value, fresh.ETag, fresh.LastModified)
+	return value, nil
 }
 
 // GetUserStatus gets user status with caching
-func (s *GitHubService) GetUserStatus(username string, useCache bool) (*models.APIResponse, error) {
+// Stale entries are returned immediately while a background refresh updates them.
+// Users GitHub reported as missing are remembered for NegativeCacheTTL.
+func (s *GitHubService) GetUserStatus(ctx context.Context, username string, useCache bool) (*models.APIResponse, error) {
+	key := strings.ToLower(username)
 	if useCache {
-		if cachedUser, found := s.cache.Get(username); found {
+		if _, missing := s.missCache.Get(key); missing {
+			return &models.APIResponse{
+				Error:   true,
+				Cached:  true,
+				Message: ErrUserNotFound.Error(),
+			}, ErrUserNotFound
+		}
+		if cachedUser, stale, found := s.cache.Lookup(key); found {
+			if stale {
+				go s.refreshUser(username)
+			}
 			return &models.APIResponse{
 				Error:  false,
 				Cached: true,
+				Stale:  stale,
 				Data:   cachedUser,
 			}, nil
 		}
 	}
 
-	user, err := s.FetchUser(username)
+	user, err := s.FetchUser(ctx, username)
 	if err != nil {
+		if useCache && errors.Is(err, ErrUserNotFound) {
+			s.missCache.Set(key, struct{}{})
+		}
 		return &models.APIResponse{
 			Error:   true,
 			Message: err.Error(),
 		}, err
 	}
 
-	if useCache {
-		s.cache.Set(username, *user)
-	}
+	s.missCache.Delete(key)
 
 	return &models.APIResponse{
 		Error:  false,
@@ -110,8 +331,23 @@ func (s *GitHubService) GetUserStatus(username string, useCache bool) (*models.A
 	}, nil
 }
 
-// GetBatchStatus fetches multiple users concurrently
-func (s *GitHubService) GetBatchStatus(usernames []string) *models.BatchResponse {
+// refreshUser re-fetches a stale user entry in the background
+func (s *GitHubService) refreshUser(username string) {
+	_, err := s.FetchUser(context.Background(), username)
+	if errors.Is(err, ErrUserNotFound) {
+		key := strings.ToLower(username)
+		s.cache.Delete(key)
+		s.missCache.Set(key, struct{}{})
+		return
+	}
+	if err != nil {
+		log.Printf("⚠️ [Cache] Background refresh failed for %s: %v", username, err)
+	}
+}
+
+// GetBatchStatus fetches multiple users concurrently. Every lookup shares ctx,
+// so a cancelled or expired ctx stops the whole batch.
+func (s *GitHubService) GetBatchStatus(ctx context.Context, usernames []string) *models.BatchResponse {
 	results := make(map[string]interface{})
 	var mu sync.Mutex
 	var wg sync.WaitGroup
@@ -124,7 +360,7 @@ func (s *GitHubService) GetBatchStatus(usernames []string) *models.BatchResponse
 		wg.Add(1)
 		go func(user string) {
 			defer wg.Done()
-			result, _ := s.GetUserStatus(user, true)
+			result, _ := s.GetUserStatus(ctx, user, true)
 			mu.Lock()
 			results[user] = result
 			mu.Unlock()
@@ -139,81 +375,192 @@ func (s *GitHubService) GetBatchStatus(usernames []string) *models.BatchResponse
 	}
 }
 
-// FetchUserRepos fetches user repositories
-func (s *GitHubService) FetchUserRepos(username string) ([]models.GitHubRepo, error) {
-	url := fmt.Sprintf("https://api.github.com/users/%s/repos?per_page=100&sort=updated", username)
+// FetchUserRepos fetches every page of a user's repositories, up to MaxPages,
+// and caches them, revalidating any cached copy with a conditional request.
+// Like the profile, users' own listings are fetched with the shared tokens.
+// Concurrent calls for the same username share a single upstream request.
+func (s *GitHubService) FetchUserRepos(ctx context.Context, username string) (models.RepoList, error) {
+	key := strings.ToLower(username)
+	return s.repoFlight.DoContext(ctx, key, func(ctx context.Context) (models.RepoList, error) {
+		ctx = publicContext(ctx, username)
+		return revalidate(s.repoCache, key, func(v github.Validators) (models.RepoList, github.Validators, error) {
+			return s.fetchUserRepos(ctx, username, v)
+		})
+	})
+}
+
+func (s *GitHubService) fetchUserRepos(ctx context.Context, username string, v github.Validators) (models.RepoList, github.Validators, error) {
+	path := fmt.Sprintf("/users/%s/repos?per_page=100&sort=updated", url.PathEscape(username))
 
-	req, err := http.NewRequest("GET", url, nil)
+	repos, truncated, validators, err := fetchAll[models.GitHubRepo](ctx, s.client, path, v, s.config.MaxPages)
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %v", err)
+		return models.RepoList{}, v, err
 	}
-	s.setAuthHeaders(req)
+	return models.RepoList{Repos: repos, Truncated: truncated}, validators, nil
+}
+
+// FetchUserEvents fetches every page of a user's events for streak calculation,
+// up to MaxPages, and caches them, revalidating any cached copy with a
+// conditional request. Users' own events are fetched with the shared tokens
+// because GitHub includes private events when a user lists their own.
+// Concurrent calls for the same username share a single upstream request.
+func (s *GitHubService) FetchUserEvents(ctx context.Context, username string) (models.EventList, error) {
+	key := strings.ToLower(username)
+	return s.eventFlight.DoContext(ctx, key, func(ctx context.Context) (models.EventList, error) {
+		ctx = publicContext(ctx, username)
+		return revalidate(s.eventCache, key, func(v github.Validators) (models.EventList, github.Validators, error) {
+			return s.fetchUserEvents(ctx, username, v)
+		})
+	})
+}
 
-	resp, err := s.httpClient.Do(req)
+func (s *GitHubService) fetchUserEvents(ctx context.Context, username string, v github.Validators) (models.EventList, github.Validators, error) {
+	path := fmt.Sprintf("/users/%s/events?per_page=100", url.PathEscape(username))
+
+	events, truncated, validators, err := fetchAll[models.GitHubEvent](ctx, s.client, path, v, s.config.MaxPages)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %v", err)
+		return models.EventList{}, v, err
 	}
-	defer resp.Body.Close()
+	return models.EventList{Events: events, Truncated: truncated}, validators, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GitHub API returned status: %d", resp.StatusCode)
+// fetchAll collects a paginated listing by following Link rel="next",
+// stopping after maxPages (0 for no cap). truncated reports that the cap
+// was hit with pages still unread. The first page is revalidated with v;
+// if it is unchanged fetchAll returns an error matching github.ErrNotModified.
+func fetchAll[T any](ctx context.Context, client *github.Client, path string, v github.Validators, maxPages int) (items []T, truncated bool, validators github.Validators, err error) {
+	pages := github.NewPaginator[T](client, path).Revalidate(v)
+	for pages.HasNext() {
+		if maxPages > 0 && pages.Pages() >= maxPages {
+			return items, true, pages.Validators(), nil
+		}
+		page, err := pages.Next(ctx)
+		if err != nil {
+			return nil, false, v, err
+		}
+		items = append(items, page...)
 	}
+	return items, false, pages.Validators(), nil
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response: %v", err)
+// GetUserRepos gets user repositories with caching; an expired entry is
+// revalidated with GitHub rather than fetched again in full
+func (s *GitHubService) GetUserRepos(ctx context.Context, username string, useCache bool) (models.RepoList, error) {
+	if useCache {
+		if repos, found := s.repoCache.Get(strings.ToLower(username)); found {
+			return repos, nil
+		}
 	}
 
-	var repos []models.GitHubRepo
-	if err := json.Unmarshal(body, &repos); err != nil {
-		return nil, fmt.Errorf("error parsing JSON: %v", err)
+	repos, err := s.FetchUserRepos(ctx, username)
+	if err != nil {
+		return models.RepoList{}, err
 	}
 
 	return repos, nil
 }
 
-// FetchUserEvents fetches user events for streak calculation
-func (s *GitHubService) FetchUserEvents(username string) ([]models.GitHubEvent, error) {
-	url := fmt.Sprintf("https://api.github.com/users/%s/events?per_page=100", username)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %v", err)
+// GetUserEvents gets user events with caching; an expired entry is
+// revalidated with GitHub rather than fetched again in full
+func (s *GitHubService) GetUserEvents(ctx context.Context, username string, useCache bool) (models.EventList, error) {
+	if useCache {
+		if events, found := s.eventCache.Get(strings.ToLower(username)); found {
+			return events, nil
+		}
 	}
-	s.setAuthHeaders(req)
 
-	resp, err := s.httpClient.Do(req)
+	events, err := s.FetchUserEvents(ctx, username)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %v", err)
+		return models.EventList{}, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GitHub API returned status: %d", resp.StatusCode)
-	}
+	return events, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response: %v", err)
-	}
+// ClearCache removes all cached users, repositories, events, calendars,
+// repository languages, repository analytics, health, contributor
+// concentration and velocity, collaboration graphs and connections
+func (s *GitHubService) ClearCache() {
+	s.cache.Clear()
+	s.repoCache.Clear()
+	s.eventCache.Clear()
+	s.calendarCache.Clear()
+	s.languageCache.Clear()
+	s.analyticsCache.Clear()
+	s.healthCache.Clear()
+	s.concentrationCache.Clear()
+	s.velocityCache.Clear()
+	s.networkCache.Clear()
+	s.connectionCache.Clear()
+	s.missCache.Clear()
+}
 
-	var events []models.GitHubEvent
-	if err := json.Unmarshal(body, &events); err != nil {
-		return nil, fmt.Errorf("error parsing JSON: %v", err)
+// Caches returns every cache by name for administration
+func (s *GitHubService) Caches() map[string]cache.Manager {
+	return map[string]cache.Manager{
+		"user":          s.cache,
+		"repos":         s.repoCache,
+		"events":        s.eventCache,
+		"calendar":      s.calendarCache,
+		"languages":     s.languageCache,
+		"analytics":     s.analyticsCache,
+		"health":        s.healthCache,
+		"concentration": s.concentrationCache,
+		"velocity":      s.velocityCache,
+		"network":       s.networkCache,
+		"connections":   s.connectionCache,
+		"negative":      s.missCache,
 	}
+}
 
-	return events, nil
+// CacheStats returns user cache statistics including coalesced upstream calls,
+// with revalidations counted across the user, repo, event and language caches
+// and the remaining GitHub quota
+func (s *GitHubService) CacheStats() models.CacheStats {
+	stats := s.cache.Stats()
+	stats.NegativeHits = s.missCache.Stats().Hits
+	for _, tier := range []cache.Manager{s.repoCache, s.eventCache, s.languageCache} {
+		tierStats := tier.Stats()
+		stats.Revalidations += tierStats.Revalidations
+		stats.NotModified += tierStats.NotModified
+	}
+	stats.Coalesced = s.userFlight.Coalesced() + s.repoFlight.Coalesced() + s.eventFlight.Coalesced() +
+		s.calendarFlight.Coalesced() + s.languageFlight.Coalesced() + s.analyticsFlight.Coalesced() +
+		s.healthFlight.Coalesced() + s.concentrationFlight.Coalesced() +
+		s.velocityFlight.Coalesced() + s.networkFlight.Coalesced() + s.connectionFlight.Coalesced()
+	stats.GitHubRateLimit = s.client.RateLimit()
+	return stats
+}
+
+// languageWorkers bounds the concurrent languages calls of one tech stack
+const languageWorkers = 5
+
+// TechStackOptions selects the repositories and weighting of a tech stack
+type TechStackOptions struct {
+	// Weighted adds a breakdown by bytes of code from each repository's
+	// languages endpoint to the count of primary languages
+	Weighted        bool
+	ExcludeForks    bool
+	ExcludeArchived bool
 }
 
 // GetTechStack calculates tech stack from repos
-func (s *GitHubService) GetTechStack(username string) (*models.TechStack, error) {
-	repos, err := s.FetchUserRepos(username)
+func (s *GitHubService) GetTechStack(ctx context.Context, username string, useCache bool, opts TechStackOptions) (*models.TechStack, error) {
+	repos, err := s.GetUserRepos(ctx, username, useCache)
 	if err != nil {
 		return nil, err
 	}
 
+	var selected []models.GitHubRepo
+	for _, repo := range repos.Repos {
+		if opts.ExcludeForks && repo.Fork || opts.ExcludeArchived && repo.Archived {
+			continue
+		}
+		selected = append(selected, repo)
+	}
+
 	languages := make(map[string]int)
-	for _, repo := range repos {
+	for _, repo := range selected {
 		if repo.Language != "" {
 			languages[repo.Language]++
 		}
@@ -228,58 +575,311 @@ func (s *GitHubService) GetTechStack(username string) (*models.TechStack, error)
 		}
 	}
 
-	return &models.TechStack{
+	stack := &models.TechStack{
 		Languages:   languages,
 		TopLanguage: topLang,
-		TotalRepos:  len(repos),
-	}, nil
+		TotalRepos:  len(selected),
+		Truncated:   repos.Truncated,
+		Mode:        "count",
+	}
+	if !opts.Weighted {
+		return stack, nil
+	}
+
+	if err := s.weighLanguages(ctx, username, selected, useCache, stack); err != nil {
+		return nil, err
+	}
+	return stack, nil
 }
 
-// GetStreak calculates contribution streak from events
-func (s *GitHubService) GetStreak(username string) (*models.StreakInfo, error) {
-	events, err := s.FetchUserEvents(username)
+// weighLanguages fills in the byte-weighted breakdown of stack from the
+// MaxLanguageRepos most recently pushed repos. Repositories whose languages
+// cannot be fetched are left out rather than failing the whole stack.
+func (s *GitHubService) weighLanguages(ctx context.Context, username string, repos []models.GitHubRepo, useCache bool, stack *models.TechStack) error {
+	repos = append([]models.GitHubRepo(nil), repos...)
+	sort.SliceStable(repos, func(i, j int) bool {
+		return repos[i].PushedAt > repos[j].PushedAt
+	})
+	if limit := s.config.MaxLanguageRepos; limit > 0 && len(repos) > limit {
+		repos = repos[:limit]
+	}
+
+	results := make([]map[string]int64, len(repos))
+	sem := make(chan struct{}, languageWorkers)
+	var wg sync.WaitGroup
+	for i, repo := range repos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			owner, name := username, repo.Name
+			if before, after, ok := strings.Cut(repo.FullName, "/"); ok {
+				owner, name = before, after
+			}
+			langs, err := s.GetRepoLanguages(ctx, owner, name, useCache)
+			if err != nil {
+				log.Printf("⚠️ [TechStack] Languages unavailable for %s/%s: %v", owner, name, err)
+				return
+			}
+			results[i] = langs
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	cutoff := timeNow().AddDate(-1, 0, 0)
+	all := make(map[string]int64)
+	recent := make(map[string]int64)
+	for i, repo := range repos {
+		pushedAt, err := time.Parse(time.RFC3339, repo.PushedAt)
+		isRecent := err == nil && pushedAt.After(cutoff)
+		for lang, bytes := range results[i] {
+			all[lang] += bytes
+			if isRecent {
+				recent[lang] += bytes
+			}
+		}
+	}
+
+	stack.Mode = "bytes"
+	stack.Breakdown = languageShares(all)
+	stack.Recent = languageShares(recent)
+	stack.ReposAnalyzed = len(repos)
+	if len(stack.Breakdown) > 0 {
+		stack.TopLanguage = stack.Breakdown[0].Language
+	}
+	return nil
+}
+
+// languageShares orders languages by bytes, largest first, with each
+// language's percentage of the total rounded to one decimal place
+func languageShares(bytes map[string]int64) []models.LanguageShare {
+	var total int64
+	for _, n := range bytes {
+		total += n
+	}
+	if total == 0 {
+		return nil
+	}
+
+	shares := make([]models.LanguageShare, 0, len(bytes))
+	for lang, n := range bytes {
+		shares = append(shares, models.LanguageShare{
+			Language: lang,
+			Bytes:    n,
+			Percent:  math.Round(float64(n)*1000/float64(total)) / 10,
+		})
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Bytes != shares[j].Bytes {
+			return shares[i].Bytes > shares[j].Bytes
+		}
+		return shares[i].Language < shares[j].Language
+	})
+	return shares
+}
+
+// FetchRepoLanguages fetches the bytes of code per language in a repository
+// and caches them, revalidating any cached copy with a conditional request.
+// They are only ever served alongside a repository the caller can see, so
+// a session token is used as is. Concurrent calls for the same repository
+// share a single upstream request.
+func (s *GitHubService) FetchRepoLanguages(ctx context.Context, owner, repo string) (map[string]int64, error) {
+	key := repoKey(owner, repo)
+	return s.languageFlight.DoContext(ctx, key, func(ctx context.Context) (map[string]int64, error) {
+		return revalidate(s.languageCache, key, func(v github.Validators) (map[string]int64, github.Validators, error) {
+			var languages map[string]int64
+			path := fmt.Sprintf("/repos/%s/%s/languages", url.PathEscape(owner), url.PathEscape(repo))
+			resp, notModified, err := s.client.GetConditional(ctx, path, v, &languages)
+			switch {
+			case err != nil:
+				return nil, github.Validators{}, err
+			case notModified:
+				return nil, v, github.ErrNotModified
+			}
+			return languages, github.ValidatorsFrom(resp), nil
+		})
+	})
+}
+
+// GetRepoLanguages gets a repository's languages with caching; an expired
+// entry is revalidated with GitHub rather than fetched again in full
+func (s *GitHubService) GetRepoLanguages(ctx context.Context, owner, repo string, useCache bool) (map[string]int64, error) {
+	if useCache {
+		if languages, found := s.languageCache.Get(repoKey(owner, repo)); found {
+			return languages, nil
+		}
+	}
+	return s.FetchRepoLanguages(ctx, owner, repo)
+}
+
+// contributionsQuery reads a user's contribution calendar for the past year
+const contributionsQuery = `query($login: String!) {
+  user(login: $login) {
+    contributionsCollection {
+      contributionCalendar {
+        totalContributions
+        weeks { contributionDays { date contributionCount contributionLevel } }
+      }
+    }
+  }
+}`
+
+// contributionLevels maps GitHub's ContributionLevel enum to heatmap levels
+var contributionLevels = map[string]int{
+	"NONE":            0,
+	"FIRST_QUARTILE":  1,
+	"SECOND_QUARTILE": 2,
+	"THIRD_QUARTILE":  3,
+	"FOURTH_QUARTILE": 4,
+}
+
+// FetchContributions fetches a user's contribution calendar for the past year
+// from the GraphQL API and caches it. Users' own calendars are requested with
+// the shared tokens, so the cached calendar only counts private
+// contributions the user has chosen to show on their public profile.
+// Concurrent calls for the same username share a single upstream request.
+func (s *GitHubService) FetchContributions(ctx context.Context, username string) (models.ContributionCalendar, error) {
+	key := strings.ToLower(username)
+	return s.calendarFlight.DoContext(ctx, key, func(ctx context.Context) (models.ContributionCalendar, error) {
+		var data struct {
+			User *struct {
+				ContributionsCollection struct {
+					ContributionCalendar struct {
+						TotalContributions int `json:"totalContributions"`
+						Weeks              []struct {
+							ContributionDays []struct {
+								Date              string `json:"date"`
+								ContributionCount int    `json:"contributionCount"`
+								ContributionLevel string `json:"contributionLevel"`
+							} `json:"contributionDays"`
+						} `json:"weeks"`
+					} `json:"contributionCalendar"`
+				} `json:"contributionsCollection"`
+			} `json:"user"`
+		}
+
+		variables := map[string]interface{}{"login": username}
+		err := s.client.GraphQL(publicContext(ctx, username), contributionsQuery, variables, &data)
+		if errors.Is(err, github.ErrNotFound) || err == nil && data.User == nil {
+			return models.ContributionCalendar{}, ErrUserNotFound
+		}
+		if err != nil {
+			return models.ContributionCalendar{}, err
+		}
+
+		source := data.User.ContributionsCollection.ContributionCalendar
+		calendar := models.ContributionCalendar{TotalContributions: source.TotalContributions}
+		for _, week := range source.Weeks {
+			for _, day := range week.ContributionDays {
+				calendar.Days = append(calendar.Days, models.ContributionDay{
+					Date:  day.Date,
+					Count: day.ContributionCount,
+					Level: contributionLevels[day.ContributionLevel],
+				})
+			}
+		}
+
+		s.calendarCache.Set(key, calendar)
+		return calendar, nil
+	})
+}
+
+// GetContributions gets a user's contribution calendar with caching
+func (s *GitHubService) GetContributions(ctx context.Context, username string, useCache bool) (models.ContributionCalendar, error) {
+	if useCache {
+		if calendar, found := s.calendarCache.Get(strings.ToLower(username)); found {
+			return calendar, nil
+		}
+	}
+	return s.FetchContributions(ctx, username)
+}
+
+// GetStreak calculates contribution streaks from the contribution calendar.
+// If GraphQL is unavailable (it needs a server token) the streaks fall back
+// to recent public events, which only cover the last 90 days. Event days and
+// "today" are taken in loc; calendar days are GitHub's own and only "today"
+// moves with loc.
+func (s *GitHubService) GetStreak(ctx context.Context, username string, useCache bool, loc *time.Location) (*models.StreakInfo, error) {
+	calendar, err := s.GetContributions(ctx, username, useCache)
+	if err == nil {
+		return streakFromCalendar(calendar, loc), nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	if !errors.Is(err, github.ErrUnauthorized) {
+		log.Printf("⚠️ [Streak] Contribution calendar unavailable for %s, using events: %v", username, err)
+	}
+
+	list, err := s.GetUserEvents(ctx, username, useCache)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(events) == 0 {
-		return &models.StreakInfo{
-			CurrentStreak: 0,
-			LongestStreak: 0,
-			TotalDays:     0,
-			LastActive:    "",
-		}, nil
+	activeDays := make(map[string]bool)
+	for _, event := range list.Events {
+		if day, ok := activityDay(event.CreatedAt, loc); ok {
+			activeDays[day] = true
+		}
 	}
 
+	streak := computeStreak(activeDays, loc)
+	streak.Source = "events"
+	streak.Truncated = list.Truncated
+	return streak, nil
+}
+
+// streakFromCalendar computes streaks from per-day contribution counts
+func streakFromCalendar(calendar models.ContributionCalendar, loc *time.Location) *models.StreakInfo {
 	activeDays := make(map[string]bool)
-	for _, event := range events {
-		if event.CreatedAt != "" {
-			day := event.CreatedAt[:10]
-			activeDays[day] = true
+	for _, day := range calendar.Days {
+		if day.Count > 0 {
+			activeDays[day.Date] = true
 		}
 	}
 
-	var days []string
-	for day := range activeDays {
-		days = append(days, day)
+	streak := computeStreak(activeDays, loc)
+	streak.Source = "calendar"
+	streak.TotalContributions = calendar.TotalContributions
+	streak.Calendar = calendar.Days
+	return streak
+}
+
+// activityDay returns the YYYY-MM-DD date in loc of an RFC 3339 timestamp
+func activityDay(createdAt string, loc *time.Location) (string, bool) {
+	t, err := time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return "", false
 	}
+	return t.In(loc).Format("2006-01-02"), true
+}
 
-	for i := 0; i < len(days)-1; i++ {
-		for j := i + 1; j < len(days); j++ {
-			if days[i] < days[j] {
-				days[i], days[j] = days[j], days[i]
-			}
-		}
+// computeStreak derives the current and longest run of consecutive active
+// days. The current streak is still alive if the user was active yesterday
+// but not yet today, with today taken in loc.
+func computeStreak(activeDays map[string]bool, loc *time.Location) *models.StreakInfo {
+	if len(activeDays) == 0 {
+		return &models.StreakInfo{}
 	}
 
-	lastActive := ""
-	if len(days) > 0 {
-		lastActive = days[0]
+	days := make([]string, 0, len(activeDays))
+	for day := range activeDays {
+		days = append(days, day)
 	}
+	sort.Sort(sort.Reverse(sort.StringSlice(days)))
 
 	currentStreak := 0
-	now := timeNow()
-	today := fmt.Sprintf("%d-%02d-%02d", now.Year(), now.Month(), now.Day())
+	today := timeNow().In(loc).Format("2006-01-02")
 
 	checkDate := today
 	for {
@@ -316,8 +916,8 @@ func (s *GitHubService) GetStreak(username string) (*models.StreakInfo, error) {
 		CurrentStreak: currentStreak,
 		LongestStreak: longestStreak,
 		TotalDays:     len(activeDays),
-		LastActive:    lastActive,
-	}, nil
+		LastActive:    days[0],
+	}
 }
 
 func previousDay(date string) string {
@@ -333,9 +933,10 @@ func isConsecutive(day1, day2 string) bool {
 	return previousDay(day2) == day1
 }
 
-// GetExtendedUserInfo fetches user info with tech stack and streak
-func (s *GitHubService) GetExtendedUserInfo(username string, useCache bool) (*models.UserExtendedInfo, error) {
-	userResp, err := s.GetUserStatus(username, useCache)
+// GetExtendedUserInfo fetches user info with tech stack and streak, with
+// streak days counted in loc
+func (s *GitHubService) GetExtendedUserInfo(ctx context.Context, username string, useCache bool, loc *time.Location, opts TechStackOptions) (*models.UserExtendedInfo, error) {
+	userResp, err := s.GetUserStatus(ctx, username, useCache)
 	if err != nil {
 		return nil, err
 	}
@@ -357,14 +958,17 @@ func (s *GitHubService) GetExtendedUserInfo(username string, useCache bool) (*mo
 	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		techStack, techErr = s.GetTechStack(username)
+		techStack, techErr = s.GetTechStack(ctx, username, useCache, opts)
 	}()
 	go func() {
 		defer wg.Done()
-		streak, streakErr = s.GetStreak(username)
+		streak, streakErr = s.GetStreak(ctx, username, useCache, loc)
 	}()
 	wg.Wait()
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if techErr != nil {
 		techStack = &models.TechStack{Languages: make(map[string]int)}
 	}
@@ -0,0 +1,585 @@
+// Package service provides tests for the GitHub service
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github-api/backend/internal/cache"
+	"github-api/backend/internal/config"
+	"github-api/backend/internal/github"
+	"github-api/backend/internal/models"
+)
+
+func newTestService(t *testing.T, handler http.HandlerFunc) *GitHubService {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	cfg := &config.Config{
+		GitHubAPIURL:     server.URL,
+		Timeout:          5 * time.Second,
+		CacheTTL:         time.Minute,
+		NegativeCacheTTL: time.Minute,
+		MaxCacheSize:     100,
+	}
+	return NewGitHubService(cfg, cache.New[string, models.GitHubUser](cfg.MaxCacheSize, cfg.CacheTTL))
+}
+
+// fixture is a fake GitHub API answering each path with a JSON body, a
+// status code or a handler; other paths are not found. The test client has
+// no token of its own, so a request carrying one used the session token;
+// the Authorization header last seen on each path is recorded.
+type fixture struct {
+	t      *testing.T
+	routes map[string]any
+	calls  atomic.Int32
+
+	mu   sync.Mutex
+	auth map[string]string
+}
+
+// newFixture starts a GitHub fixture and a service calling it
+func newFixture(t *testing.T, routes map[string]any) (*GitHubService, *fixture) {
+	t.Helper()
+	fx := &fixture{t: t, routes: routes, auth: make(map[string]string)}
+	return newTestService(t, fx.serve), fx
+}
+
+func (fx *fixture) serve(w http.ResponseWriter, r *http.Request) {
+	fx.calls.Add(1)
+	fx.mu.Lock()
+	fx.auth[r.URL.Path] = r.Header.Get("Authorization")
+	fx.mu.Unlock()
+	switch route := fx.routes[r.URL.Path].(type) {
+	case string:
+		w.Write([]byte(route))
+	case int:
+		w.WriteHeader(route)
+	case func(http.ResponseWriter, *http.Request):
+		route(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// authorization returns the Authorization header last sent to path
+func (fx *fixture) authorization(path string) string {
+	fx.mu.Lock()
+	defer fx.mu.Unlock()
+	return fx.auth[path]
+}
+
+// nextPage sets a Link header pointing at page 2 of the requested path
+func nextPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?per_page=100&page=2>; rel="next"`, r.Host, r.URL.Path))
+}
+
+// sessionContext carries the token of a signed-in user, viewer, as
+// OptionalAuth attaches it
+func sessionContext() context.Context {
+	return github.ContextWithUserToken(context.Background(), "user-token", "viewer")
+}
+
+func TestNegativeCaching(t *testing.T) {
+	var calls atomic.Int32
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.NotFound(w, r)
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := svc.GetUserStatus(context.Background(), "ghost", true); !errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("Expected ErrUserNotFound, got %v", err)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", calls.Load())
+	}
+	if hits := svc.CacheStats().NegativeHits; hits != 2 {
+		t.Errorf("Expected 2 negative hits, got %d", hits)
+	}
+
+	// no_cache bypasses the negative entry
+	if _, err := svc.GetUserStatus(context.Background(), "ghost", false); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("Expected ErrUserNotFound, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected bypass to call upstream, got %d calls", calls.Load())
+	}
+}
+
+func TestNegativeEntryClearedOnSuccess(t *testing.T) {
+	var exists atomic.Bool
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		if !exists.Load() {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"login":"octocat"}`))
+	})
+
+	svc.GetUserStatus(context.Background(), "octocat", true)
+	exists.Store(true)
+
+	if _, err := svc.GetUserStatus(context.Background(), "octocat", false); err != nil {
+		t.Fatalf("Expected success after user was created, got %v", err)
+	}
+	resp, err := svc.GetUserStatus(context.Background(), "octocat", true)
+	if err != nil || resp.Error {
+		t.Errorf("Negative entry should be cleared after a successful lookup, got %v", err)
+	}
+}
+
+func TestFetchUserReposFollowsPagination(t *testing.T) {
+	var base string
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s/users/octocat/repos?per_page=100&page=%d>; rel="next"`, base, page+1))
+		fmt.Fprintf(w, `[{"name":"repo-%d","language":"Go","stargazers_count":1}]`, page)
+	})
+	base = svc.GitHub().BaseURL()
+
+	// The listing never ends, so only the cap stops it
+	svc.config.MaxPages = 4
+	repos, err := svc.FetchUserRepos(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("FetchUserRepos failed: %v", err)
+	}
+	if len(repos.Repos) != 4 || repos.Repos[3].Name != "repo-4" {
+		t.Errorf("Expected 4 pages of repos, got %+v", repos.Repos)
+	}
+	if !repos.Truncated {
+		t.Error("Hitting the page cap should mark the list truncated")
+	}
+
+	stack, err := svc.GetTechStack(context.Background(), "octocat", false, TechStackOptions{})
+	if err != nil {
+		t.Fatalf("GetTechStack failed: %v", err)
+	}
+	if stack.TotalRepos != 4 || !stack.Truncated {
+		t.Errorf("Expected a truncated tech stack over 4 repos, got %+v", stack)
+	}
+}
+
+func TestWeightedTechStack(t *testing.T) {
+	defer func(now func() time.Time) { timeNow = now }(timeNow)
+	timeNow = func() time.Time { return time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC) }
+
+	svc, fx := newFixture(t, map[string]any{
+		"/users/octocat/repos": `[
+			{"name":"api","full_name":"octocat/api","language":"Go","pushed_at":"2024-05-01T00:00:00Z"},
+			{"name":"site","full_name":"octocat/site","language":"TypeScript","pushed_at":"2022-01-01T00:00:00Z"},
+			{"name":"old","full_name":"octocat/old","language":"Python","archived":true,"pushed_at":"2020-01-01T00:00:00Z"},
+			{"name":"linux","full_name":"octocat/linux","language":"C","fork":true,"pushed_at":"2024-05-20T00:00:00Z"}
+		]`,
+		"/repos/octocat/api/languages":  `{"Go":7000,"Shell":1000}`,
+		"/repos/octocat/site/languages": `{"TypeScript":2000}`,
+	})
+
+	opts := TechStackOptions{Weighted: true, ExcludeForks: true, ExcludeArchived: true}
+	stack, err := svc.GetTechStack(sessionContext(), "octocat", true, opts)
+	if err != nil {
+		t.Fatalf("GetTechStack failed: %v", err)
+	}
+
+	if stack.Mode != "bytes" || stack.TotalRepos != 2 || stack.ReposAnalyzed != 2 || stack.TopLanguage != "Go" {
+		t.Errorf("Unexpected stack %+v", stack)
+	}
+	want := []models.LanguageShare{
+		{Language: "Go", Bytes: 7000, Percent: 70},
+		{Language: "TypeScript", Bytes: 2000, Percent: 20},
+		{Language: "Shell", Bytes: 1000, Percent: 10},
+	}
+	if fmt.Sprint(stack.Breakdown) != fmt.Sprint(want) {
+		t.Errorf("Expected breakdown %v, got %v", want, stack.Breakdown)
+	}
+	if len(stack.Recent) != 2 || stack.Recent[0].Percent != 87.5 || stack.Recent[1].Language != "Shell" {
+		t.Errorf("Only repos pushed in the last year should count as recent, got %v", stack.Recent)
+	}
+
+	if _, err := svc.GetTechStack(sessionContext(), "octocat", true, opts); err != nil {
+		t.Fatalf("GetTechStack failed: %v", err)
+	}
+	// The listing and the languages of the two kept repos, each fetched once
+	if fx.calls.Load() != 3 {
+		t.Errorf("Repo languages should be cached, got %d calls", fx.calls.Load())
+	}
+}
+
+func TestFetchUserEventsReadsEveryPage(t *testing.T) {
+	var base string
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/users/octocat/events?per_page=100&page=2>; rel="next"`, base))
+			w.Write([]byte(`[{"type":"PushEvent","created_at":"2024-01-02T00:00:00Z"}]`))
+			return
+		}
+		w.Write([]byte(`[{"type":"PushEvent","created_at":"2024-01-01T00:00:00Z"}]`))
+	})
+	base = svc.GitHub().BaseURL()
+	svc.config.MaxPages = 10
+
+	events, err := svc.FetchUserEvents(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("FetchUserEvents failed: %v", err)
+	}
+	if len(events.Events) != 2 || events.Truncated {
+		t.Errorf("Expected both pages without truncation, got %+v", events)
+	}
+}
+
+func TestUserListingsCachedCaseInsensitively(t *testing.T) {
+	var calls atomic.Int32
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/events"):
+			w.Write([]byte(`[{"type":"PushEvent","created_at":"2024-01-01T00:00:00Z"}]`))
+		case strings.HasSuffix(r.URL.Path, "/repos"):
+			w.Write([]byte(`[{"name":"hello","full_name":"octocat/hello"}]`))
+		default:
+			w.Write([]byte(`{"login":"octocat"}`))
+		}
+	})
+
+	for _, name := range []string{"Octocat", "octocat", "OCTOCAT"} {
+		if _, err := svc.GetUserStatus(context.Background(), name, true); err != nil {
+			t.Fatalf("GetUserStatus(%s) failed: %v", name, err)
+		}
+		if _, err := svc.GetUserRepos(context.Background(), name, true); err != nil {
+			t.Fatalf("GetUserRepos(%s) failed: %v", name, err)
+		}
+		if _, err := svc.GetUserEvents(context.Background(), name, true); err != nil {
+			t.Fatalf("GetUserEvents(%s) failed: %v", name, err)
+		}
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected one request per listing whatever the case, got %d", calls.Load())
+	}
+}
+
+func TestContributionsCachedCaseInsensitively(t *testing.T) {
+	var calls atomic.Int32
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"data":{"user":{"contributionsCollection":{"contributionCalendar":{"totalContributions":1,"weeks":[]}}}}}`))
+	})
+	svc.client = github.NewPoolClient(svc.client.BaseURL(), []string{"shared"}, time.Second)
+
+	for _, name := range []string{"Octocat", "octocat", "OCTOCAT"} {
+		if _, err := svc.GetContributions(context.Background(), name, true); err != nil {
+			t.Fatalf("GetContributions(%s) failed: %v", name, err)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected one calendar query whatever the case, got %d", calls.Load())
+	}
+}
+
+func TestRepoLanguagesCachedCaseInsensitively(t *testing.T) {
+	var calls atomic.Int32
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"Go":1200}`))
+	})
+
+	for _, repo := range [][2]string{{"Octocat", "Hello"}, {"octocat", "hello"}} {
+		if _, err := svc.GetRepoLanguages(context.Background(), repo[0], repo[1], true); err != nil {
+			t.Fatalf("GetRepoLanguages(%s/%s) failed: %v", repo[0], repo[1], err)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected one languages request whatever the case, got %d", calls.Load())
+	}
+}
+
+func TestSessionTokenOnlyDroppedForOwnLookups(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		session string
+		auth    string
+	}{
+		{"other user", "viewer", "Bearer user-token"},
+		{"own account", "Octocat", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			svc, fx := newFixture(t, map[string]any{
+				"/users/octocat":        `{"login":"octocat"}`,
+				"/users/octocat/repos":  `[{"name":"hello","full_name":"octocat/hello"}]`,
+				"/users/octocat/events": `[{"type":"PushEvent","created_at":"2024-01-01T00:00:00Z","repo":{"name":"octocat/secret"}}]`,
+			})
+
+			ctx := github.ContextWithUserToken(context.Background(), "user-token", tc.session)
+			if _, err := svc.GetUserStatus(ctx, "octocat", true); err != nil {
+				t.Fatalf("GetUserStatus failed: %v", err)
+			}
+			if _, err := svc.GetUserRepos(ctx, "octocat", true); err != nil {
+				t.Fatalf("GetUserRepos failed: %v", err)
+			}
+			if _, err := svc.GetUserEvents(ctx, "octocat", true); err != nil {
+				t.Fatalf("GetUserEvents failed: %v", err)
+			}
+
+			for _, path := range []string{"/users/octocat", "/users/octocat/repos", "/users/octocat/events"} {
+				if auth := fx.authorization(path); auth != tc.auth {
+					t.Errorf("Expected %s to be sent with %q, got %q", path, tc.auth, auth)
+				}
+			}
+		})
+	}
+}
+
+func TestExpiredUserRevalidatedWithETag(t *testing.T) {
+	var full, conditional atomic.Int32
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"login":"octocat","followers":7}`))
+	})
+
+	if _, err := svc.FetchUser(context.Background(), "octocat"); err != nil {
+		t.Fatalf("FetchUser failed: %v", err)
+	}
+
+	// Expire the entry but keep it around for revalidation
+	user, _ := svc.cache.L1().Peek("octocat")
+	svc.cache.L1().SetStaleTTL(time.Hour)
+	svc.cache.L1().SetWithValidators("octocat", user.Data, -time.Second, user.ETag, user.LastModified)
+
+	resp, err := svc.GetUserStatus(context.Background(), "octocat", false)
+	if err != nil {
+		t.Fatalf("GetUserStatus failed: %v", err)
+	}
+	if got := resp.Data.(*models.GitHubUser); got.Followers != 7 {
+		t.Errorf("Expected the cached user after a 304, got %+v", got)
+	}
+	if full.Load() != 1 || conditional.Load() != 1 {
+		t.Errorf("Expected 1 full and 1 conditional request, got %d and %d", full.Load(), conditional.Load())
+	}
+
+	stats := svc.CacheStats()
+	if stats.Revalidations != 1 || stats.NotModified != 1 {
+		t.Errorf("Expected 1 revalidation answered with 304, got %+v", stats)
+	}
+	if _, found := svc.cache.Get("octocat"); !found {
+		t.Error("A 304 should restart the entry's TTL")
+	}
+}
+
+func TestLookupCancelledWithCaller(t *testing.T) {
+	upstreamCancelled := make(chan struct{})
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		close(upstreamCancelled)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := svc.GetExtendedUserInfo(ctx, "octocat", true, time.UTC, TechStackOptions{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the caller's deadline, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Lookup should stop at the deadline, took %v", elapsed)
+	}
+
+	select {
+	case <-upstreamCancelled:
+	case <-time.After(time.Second):
+		t.Fatal("The GitHub request should be cancelled with the caller")
+	}
+	if _, found := svc.cache.Get("octocat"); found {
+		t.Error("A cancelled lookup must not be cached")
+	}
+}
+
+func TestStreakFromContributionCalendar(t *testing.T) {
+	defer func(now func() time.Time) { timeNow = now }(timeNow)
+	timeNow = func() time.Time { return time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC) }
+
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			t.Errorf("Expected only a GraphQL call, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"data":{"user":{"contributionsCollection":{"contributionCalendar":{
+			"totalContributions": 12,
+			"weeks": [
+				{"contributionDays": [
+					{"date":"2024-03-01","contributionCount":2,"contributionLevel":"FIRST_QUARTILE"},
+					{"date":"2024-03-02","contributionCount":3,"contributionLevel":"SECOND_QUARTILE"},
+					{"date":"2024-03-03","contributionCount":1,"contributionLevel":"FIRST_QUARTILE"},
+					{"date":"2024-03-04","contributionCount":0,"contributionLevel":"NONE"}
+				]},
+				{"contributionDays": [
+					{"date":"2024-03-08","contributionCount":1,"contributionLevel":"FIRST_QUARTILE"},
+					{"date":"2024-03-09","contributionCount":5,"contributionLevel":"FOURTH_QUARTILE"},
+					{"date":"2024-03-10","contributionCount":0,"contributionLevel":"NONE"}
+				]}
+			]
+		}}}}}`))
+	})
+	svc.client = github.NewPoolClient(svc.client.BaseURL(), []string{"shared"}, time.Second)
+
+	streak, err := svc.GetStreak(context.Background(), "octocat", true, time.UTC)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if streak.Source != "calendar" || streak.TotalContributions != 12 || len(streak.Calendar) != 7 {
+		t.Fatalf("Expected the calendar to be used, got %+v", streak)
+	}
+	if streak.CurrentStreak != 2 || streak.LongestStreak != 3 || streak.TotalDays != 5 || streak.LastActive != "2024-03-09" {
+		t.Errorf("Unexpected streak %+v", streak)
+	}
+	if streak.Calendar[5].Level != 4 {
+		t.Errorf("Expected level 4 for the busiest day, got %d", streak.Calendar[5].Level)
+	}
+}
+
+func TestStreakFallsBackToEvents(t *testing.T) {
+	defer func(now func() time.Time) { timeNow = now }(timeNow)
+	timeNow = func() time.Time { return time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC) }
+
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/graphql" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[{"created_at":"2024-03-10T08:00:00Z"},{"created_at":"2024-03-09T08:00:00Z"}]`))
+	})
+
+	streak, err := svc.GetStreak(context.Background(), "octocat", true, time.UTC)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if streak.Source != "events" || streak.CurrentStreak != 2 || streak.Calendar != nil {
+		t.Errorf("Expected an events-based streak, got %+v", streak)
+	}
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("LoadLocation(%s): %v", name, err)
+	}
+	return loc
+}
+
+func TestActivityDayInZone(t *testing.T) {
+	tests := []struct {
+		name      string
+		createdAt string
+		zone      string
+		want      string
+	}{
+		{"UTC", "2024-05-01T23:30:00Z", "UTC", "2024-05-01"},
+		{"half-hour offset before midnight", "2024-05-01T18:00:00Z", "Asia/Kolkata", "2024-05-01"},
+		{"half-hour offset past midnight", "2024-05-01T18:45:00Z", "Asia/Kolkata", "2024-05-02"},
+		{"behind UTC", "2024-05-02T03:00:00Z", "America/Los_Angeles", "2024-05-01"},
+		{"last hour of standard time", "2024-03-10T06:30:00Z", "America/New_York", "2024-03-10"},
+		{"first hour of daylight time", "2024-03-10T07:30:00Z", "America/New_York", "2024-03-10"},
+		{"end of the 23-hour day", "2024-03-11T03:59:00Z", "America/New_York", "2024-03-10"},
+		{"start of the day after spring forward", "2024-03-11T04:00:00Z", "America/New_York", "2024-03-11"},
+		{"repeated hour before fall back", "2024-11-03T05:30:00Z", "America/New_York", "2024-11-03"},
+		{"repeated hour after fall back", "2024-11-03T06:30:00Z", "America/New_York", "2024-11-03"},
+		{"end of the 25-hour day", "2024-11-04T04:59:00Z", "America/New_York", "2024-11-03"},
+		{"southern hemisphere DST", "2024-10-05T14:30:00Z", "Australia/Sydney", "2024-10-06"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := activityDay(tt.createdAt, mustLoadLocation(t, tt.zone))
+			if !ok || got != tt.want {
+				t.Errorf("activityDay(%s, %s) = %q, want %q", tt.createdAt, tt.zone, got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := activityDay("not a time", time.UTC); ok {
+		t.Error("Malformed timestamps should be skipped")
+	}
+}
+
+func TestCurrentStreakUsesZoneToday(t *testing.T) {
+	defer func(now func() time.Time) { timeNow = now }(timeNow)
+
+	activeDays := map[string]bool{"2024-03-08": true, "2024-03-09": true, "2024-03-10": true}
+	tests := []struct {
+		name string
+		now  time.Time
+		zone string
+		want int
+	}{
+		{"same day in UTC", time.Date(2024, 3, 10, 20, 0, 0, 0, time.UTC), "UTC", 3},
+		{"already tomorrow in UTC", time.Date(2024, 3, 11, 2, 0, 0, 0, time.UTC), "UTC", 3},
+		{"two days later in UTC", time.Date(2024, 3, 12, 2, 0, 0, 0, time.UTC), "UTC", 0},
+		{"still today in New York after spring forward", time.Date(2024, 3, 11, 3, 30, 0, 0, time.UTC), "America/New_York", 3},
+		{"two days later in New York", time.Date(2024, 3, 12, 4, 30, 0, 0, time.UTC), "America/New_York", 0},
+		{"yesterday counts in Kolkata", time.Date(2024, 3, 11, 18, 0, 0, 0, time.UTC), "Asia/Kolkata", 3},
+		{"streak broken in Kolkata", time.Date(2024, 3, 11, 18, 45, 0, 0, time.UTC), "Asia/Kolkata", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeNow = func() time.Time { return tt.now }
+			streak := computeStreak(activeDays, mustLoadLocation(t, tt.zone))
+			if streak.CurrentStreak != tt.want {
+				t.Errorf("Expected current streak %d, got %d", tt.want, streak.CurrentStreak)
+			}
+			if streak.LongestStreak != 3 || streak.LastActive != "2024-03-10" {
+				t.Errorf("Longest streak and last active should not depend on the zone, got %+v", streak)
+			}
+		})
+	}
+}
+
+func TestEventStreakBucketsByZone(t *testing.T) {
+	defer func(now func() time.Time) { timeNow = now }(timeNow)
+	timeNow = func() time.Time { return time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC) }
+
+	// 15:30 IST on May 1 and 00:15 IST on May 2; both fall on May 1 in UTC
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/graphql" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[{"created_at":"2024-05-01T18:45:00Z"},{"created_at":"2024-05-01T10:00:00Z"}]`))
+	})
+
+	utc, err := svc.GetStreak(context.Background(), "octocat", true, time.UTC)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ist, err := svc.GetStreak(context.Background(), "octocat", true, mustLoadLocation(t, "Asia/Kolkata"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if utc.TotalDays != 1 || utc.CurrentStreak != 1 || utc.LastActive != "2024-05-01" {
+		t.Errorf("Expected a single active day in UTC, got %+v", utc)
+	}
+	if ist.TotalDays != 2 || ist.CurrentStreak != 2 || ist.LastActive != "2024-05-02" {
+		t.Errorf("Expected two active days in IST, got %+v", ist)
+	}
+}
@@ -0,0 +1,352 @@
+// Package service provides developer collaboration graphs
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github-api/backend/internal/github"
+	"github-api/backend/internal/models"
+)
+
+const (
+	// networkOwnRepos and networkExternalRepos bound the repositories whose
+	// contributors join a collaboration graph: the developer's most starred
+	// sources and the others' repositories they were most active in
+	networkOwnRepos      = 5
+	networkExternalRepos = 5
+
+	// networkContributors is how many top contributors are read per repository
+	networkContributors = 30
+
+	// networkMaxCollaborators bounds the people in a collaboration graph
+	networkMaxCollaborators = 50
+)
+
+// FetchUserNetwork builds a weighted graph of who a developer works with.
+// Their recent events link them to the repositories of others where they
+// opened pull requests, reviewed or commented, and to the authors they
+// reviewed or answered; the contributor lists of their top repositories and
+// of those external repositories link collaborators to shared repositories.
+// Repositories and events come from the same cached listings as rankings and
+// the extended profile. Graphs are served to everyone, so developers looking
+// themselves up get theirs built with the shared tokens: with their own,
+// their events would bring private repositories into the graph. Partial
+// graphs are not cached. Concurrent calls for the same user share a single
+// fetch.
+func (s *GitHubService) FetchUserNetwork(ctx context.Context, username string) (models.UserNetwork, error) {
+	key := strings.ToLower(username)
+	return s.networkFlight.DoContext(ctx, key, func(ctx context.Context) (models.UserNetwork, error) {
+		ctx = publicContext(ctx, username)
+		var repos models.RepoList
+		var events models.EventList
+		errs := fanOut(map[string]func() error{
+			"repos": func() (err error) {
+				repos, err = s.GetUserRepos(ctx, username, true)
+				return err
+			},
+			"events": func() (err error) {
+				events, err = s.GetUserEvents(ctx, username, true)
+				return err
+			},
+		})
+		if err := ctx.Err(); err != nil {
+			return models.UserNetwork{}, err
+		}
+		if err := errs["repos"]; err != nil {
+			if errors.Is(err, github.ErrNotFound) {
+				return models.UserNetwork{}, ErrUserNotFound
+			}
+			return models.UserNetwork{}, err
+		}
+
+		network := models.UserNetwork{Username: username}
+		if err := errs["events"]; err != nil {
+			log.Printf("⚠️ [Network] events unavailable for %s: %v", username, err)
+			network.Partial = true
+		}
+
+		graph := newGraphBuilder(username)
+		external := graph.addEvents(events.Events)
+
+		contributors, err := s.networkContributors(ctx, networkRepos(repos.Repos, external))
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return models.UserNetwork{}, ctxErr
+			}
+			log.Printf("⚠️ [Network] contributors unavailable for %s: %v", username, err)
+			network.Partial = true
+		}
+		for repo, list := range contributors {
+			graph.addContributors(repo, list)
+		}
+
+		network.Nodes, network.Edges = graph.build(networkMaxCollaborators)
+		if !network.Partial {
+			s.networkCache.Set(key, network)
+		}
+		return network, nil
+	})
+}
+
+// GetUserNetwork gets a developer's collaboration graph with caching
+func (s *GitHubService) GetUserNetwork(ctx context.Context, username string, useCache bool) (models.UserNetwork, error) {
+	if useCache {
+		if network, found := s.networkCache.Get(strings.ToLower(username)); found {
+			return network, nil
+		}
+	}
+	return s.FetchUserNetwork(ctx, username)
+}
+
+// networkRepos picks the repositories whose contributors join the graph: the
+// developer's most starred non-fork repositories, then the external ones
+// they were most active in
+func networkRepos(own []models.GitHubRepo, external []string) []string {
+	sources := make([]models.GitHubRepo, 0, len(own))
+	for _, repo := range own {
+		if !repo.Fork && repo.FullName != "" {
+			sources = append(sources, repo)
+		}
+	}
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].StargazersCount > sources[j].StargazersCount
+	})
+
+	var names []string
+	for i := 0; i < len(sources) && i < networkOwnRepos; i++ {
+		names = append(names, sources[i].FullName)
+	}
+	for i := 0; i < len(external) && i < networkExternalRepos; i++ {
+		names = append(names, external[i])
+	}
+	return names
+}
+
+// networkContributors fetches the top contributors of each repository,
+// languageWorkers at a time. It returns the first error alongside the lists
+// that were fetched.
+func (s *GitHubService) networkContributors(ctx context.Context, repos []string) (map[string][]models.RepoContributor, error) {
+	lists := make(map[string][]models.RepoContributor, len(repos))
+	var firstErr error
+	var mu sync.Mutex
+	sem := make(chan struct{}, languageWorkers)
+	var wg sync.WaitGroup
+	for _, repo := range repos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			owner, name, _ := strings.Cut(repo, "/")
+			var list []models.RepoContributor
+			path := fmt.Sprintf("/repos/%s/%s/contributors?per_page=%d", url.PathEscape(owner), url.PathEscape(name), networkContributors)
+			_, err := s.client.Get(ctx, path, &list)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			lists[repo] = list
+		}()
+	}
+	wg.Wait()
+	return lists, firstErr
+}
+
+// edgeKey identifies an edge by its ends and kind
+type edgeKey struct {
+	source, target, kind string
+}
+
+// graphBuilder accumulates weighted edges around a developer
+type graphBuilder struct {
+	username string
+	center   string
+	labels   map[string]string
+	types    map[string]string
+	edges    map[edgeKey]int
+}
+
+func newGraphBuilder(username string) *graphBuilder {
+	g := &graphBuilder{
+		username: username,
+		labels:   make(map[string]string),
+		types:    make(map[string]string),
+		edges:    make(map[edgeKey]int),
+	}
+	g.center = g.user(username)
+	return g
+}
+
+// user returns the node of a person, registering it
+func (g *graphBuilder) user(login string) string {
+	id := "user:" + strings.ToLower(login)
+	if _, ok := g.labels[id]; !ok {
+		g.labels[id], g.types[id] = login, "user"
+	}
+	return id
+}
+
+// repo returns the node of a repository, registering it
+func (g *graphBuilder) repo(fullName string) string {
+	id := "repo:" + strings.ToLower(fullName)
+	if _, ok := g.labels[id]; !ok {
+		g.labels[id], g.types[id] = fullName, "repo"
+	}
+	return id
+}
+
+// isSelf reports whether login is the developer the graph is built around
+func (g *graphBuilder) isSelf(login string) bool {
+	return strings.EqualFold(login, g.username)
+}
+
+// addEvents links the developer to the external repositories they opened
+// pull requests, reviewed or commented in, and to the authors they reviewed
+// or answered. It returns the external repositories by activity, most first.
+func (g *graphBuilder) addEvents(events []models.GitHubEvent) []string {
+	activity := make(map[string]int)
+	for _, event := range events {
+		owner, _, ok := strings.Cut(event.Repo.Name, "/")
+		if !ok {
+			continue
+		}
+
+		var author, kind string
+		switch event.Type {
+		case "PullRequestEvent":
+			if event.Payload.Action != "opened" {
+				continue
+			}
+		case "PullRequestReviewEvent", "PullRequestReviewCommentEvent":
+			if event.Payload.PullRequest != nil {
+				author, kind = event.Payload.PullRequest.User.Login, "review"
+			}
+		case "IssueCommentEvent":
+			if event.Payload.Issue != nil {
+				author, kind = event.Payload.Issue.User.Login, "issue_comment"
+			}
+		default:
+			continue
+		}
+
+		if author != "" && !g.isSelf(author) && !isBot(author) {
+			g.edges[edgeKey{g.center, g.user(author), kind}]++
+		}
+		if !g.isSelf(owner) {
+			g.edges[edgeKey{g.center, g.repo(event.Repo.Name), "activity"}]++
+			activity[event.Repo.Name]++
+		}
+	}
+
+	external := make([]string, 0, len(activity))
+	for repo := range activity {
+		external = append(external, repo)
+	}
+	sort.Slice(external, func(i, j int) bool {
+		if activity[external[i]] != activity[external[j]] {
+			return activity[external[i]] > activity[external[j]]
+		}
+		return external[i] < external[j]
+	})
+	return external
+}
+
+// addContributors links every contributor of a repository to it, weighted by
+// their commits
+func (g *graphBuilder) addContributors(repo string, contributors []models.RepoContributor) {
+	target := g.repo(repo)
+	for _, contributor := range contributors {
+		if contributor.Login == "" || isBot(contributor.Login) || contributor.Contributions == 0 {
+			continue
+		}
+		g.edges[edgeKey{g.user(contributor.Login), target, "contributor"}] += contributor.Contributions
+	}
+}
+
+// build keeps the maxCollaborators people with the most direct interactions
+// plus shared repositories, then returns the nodes (the developer first,
+// then by weight) and edges of the graph
+func (g *graphBuilder) build(maxCollaborators int) ([]models.NetworkNode, []models.NetworkEdge) {
+	// Every repository in the graph is one the developer owns or worked in,
+	// so each one a collaborator contributed to counts as shared
+	score := make(map[string]int)
+	for key, weight := range g.edges {
+		switch {
+		case key.source == g.center && g.types[key.target] == "user":
+			score[key.target] += weight
+		case key.source != g.center:
+			score[key.source]++
+		}
+	}
+
+	collaborators := make([]string, 0, len(score))
+	for id := range score {
+		collaborators = append(collaborators, id)
+	}
+	sort.Slice(collaborators, func(i, j int) bool {
+		if score[collaborators[i]] != score[collaborators[j]] {
+			return score[collaborators[i]] > score[collaborators[j]]
+		}
+		return collaborators[i] < collaborators[j]
+	})
+	keep := map[string]bool{g.center: true}
+	for i, id := range collaborators {
+		if i == maxCollaborators {
+			break
+		}
+		keep[id] = true
+	}
+
+	weights := make(map[string]int)
+	edges := make([]models.NetworkEdge, 0, len(g.edges))
+	for key, weight := range g.edges {
+		if g.types[key.source] == "user" && !keep[key.source] || g.types[key.target] == "user" && !keep[key.target] {
+			continue
+		}
+		edges = append(edges, models.NetworkEdge{Source: key.source, Target: key.target, Kind: key.kind, Weight: weight})
+		weights[key.source] += weight
+		weights[key.target] += weight
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		a, b := edges[i], edges[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+		return a.Kind < b.Kind
+	})
+
+	nodes := []models.NetworkNode{{ID: g.center, Type: "user", Label: g.labels[g.center], Weight: weights[g.center]}}
+	for id, weight := range weights {
+		if id != g.center {
+			nodes = append(nodes, models.NetworkNode{ID: id, Type: g.types[id], Label: g.labels[id], Weight: weight})
+		}
+	}
+	sort.Slice(nodes[1:], func(i, j int) bool {
+		a, b := nodes[1+i], nodes[1+j]
+		if a.Weight != b.Weight {
+			return a.Weight > b.Weight
+		}
+		return a.ID < b.ID
+	})
+	return nodes, edges
+}
@@ -0,0 +1,73 @@
+// Package service provides tests for developer collaboration graphs
+package service
+
+import (
+	"testing"
+
+	"github-api/backend/internal/models"
+)
+
+func TestUserNetwork(t *testing.T) {
+	svc, _ := newFixture(t, map[string]any{
+		"/users/alice/repos": `[{"full_name":"alice/tool","stargazers_count":10},{"full_name":"alice/fork","fork":true,"stargazers_count":99}]`,
+		"/users/alice/events": `[
+			{"type":"PullRequestEvent","created_at":"2024-05-01T00:00:00Z","repo":{"name":"acme/lib"},"payload":{"action":"opened"}},
+			{"type":"PullRequestEvent","created_at":"2024-05-01T00:00:00Z","repo":{"name":"acme/lib"},"payload":{"action":"closed"}},
+			{"type":"PullRequestReviewEvent","created_at":"2024-05-01T00:00:00Z","repo":{"name":"acme/lib"},"payload":{"pull_request":{"user":{"login":"bob"}}}},
+			{"type":"IssueCommentEvent","created_at":"2024-05-01T00:00:00Z","repo":{"name":"acme/lib"},"payload":{"issue":{"user":{"login":"dependabot[bot]"}}}},
+			{"type":"IssueCommentEvent","created_at":"2024-05-01T00:00:00Z","repo":{"name":"alice/tool"},"payload":{"issue":{"user":{"login":"carol"}}}}
+		]`,
+		"/repos/alice/tool/contributors": `[{"login":"alice","contributions":50},{"login":"carol","contributions":5},{"login":"renovate[bot]","contributions":3}]`,
+		"/repos/acme/lib/contributors":   `[{"login":"bob","contributions":100},{"login":"alice","contributions":2},{"login":"dave","contributions":1}]`,
+	})
+
+	// The fixture fails any request, events and repositories included, that
+	// carries the session token
+	ctx := sessionContext()
+	network, err := svc.GetUserNetwork(ctx, "alice", true)
+	if err != nil {
+		t.Fatalf("GetUserNetwork failed: %v", err)
+	}
+	if network.Partial {
+		t.Fatal("Expected a complete graph")
+	}
+
+	edges := make(map[string]int)
+	for _, edge := range network.Edges {
+		edges[edge.Source+" "+edge.Kind+" "+edge.Target] = edge.Weight
+	}
+	want := map[string]int{
+		"user:alice activity repo:acme/lib":      3,
+		"user:alice review user:bob":             1,
+		"user:alice issue_comment user:carol":    1,
+		"user:alice contributor repo:alice/tool": 50,
+		"user:alice contributor repo:acme/lib":   2,
+		"user:carol contributor repo:alice/tool": 5,
+		"user:bob contributor repo:acme/lib":     100,
+		"user:dave contributor repo:acme/lib":    1,
+	}
+	if len(edges) != len(want) {
+		t.Errorf("Expected %d edges, got %v", len(want), edges)
+	}
+	for edge, weight := range want {
+		if edges[edge] != weight {
+			t.Errorf("Expected %q weighing %d, got %d", edge, weight, edges[edge])
+		}
+	}
+
+	if len(network.Nodes) != 6 || network.Nodes[0].ID != "user:alice" || network.Nodes[0].Weight != 57 || network.Nodes[1].ID != "repo:acme/lib" {
+		t.Errorf("Expected the developer first and nodes by weight, got %+v", network.Nodes)
+	}
+	if _, found := svc.networkCache.Get("alice"); !found {
+		t.Error("Complete graphs should be cached")
+	}
+
+	graph := newGraphBuilder("alice")
+	graph.addEvents(nil)
+	graph.addContributors("acme/lib", []models.RepoContributor{{Login: "bob", Contributions: 3}, {Login: "dave", Contributions: 1}})
+	graph.edges[edgeKey{graph.center, graph.user("bob"), "review"}] = 2
+	nodes, capped := graph.build(1)
+	if len(nodes) != 3 || len(capped) != 2 {
+		t.Errorf("Only the top collaborator should be kept, got %+v and %+v", nodes, capped)
+	}
+}
@@ -5,28 +5,25 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
-	"time"
 
+	"github-api/backend/internal/github"
 	"github-api/backend/internal/models"
 	"github-api/backend/internal/repository"
 )
 
 // PrivateDataService handles fetching and storing private GitHub data
 type PrivateDataService struct {
-	repo       *repository.PrivateDataRepository
-	httpClient *http.Client
+	repo   *repository.PrivateDataRepository
+	client *github.Client
 }
 
-// NewPrivateDataService creates a new private data service
-func NewPrivateDataService(repo *repository.PrivateDataRepository) *PrivateDataService {
+// NewPrivateDataService creates a new private data service.
+// Requests are sent with each user's own token via client.WithToken.
+func NewPrivateDataService(repo *repository.PrivateDataRepository, client *github.Client) *PrivateDataService {
 	return &PrivateDataService{
-		repo: repo,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		repo:   repo,
+		client: client,
 	}
 }
 
@@ -204,238 +201,71 @@ func (s *PrivateDataService) GetPrivateData(ctx context.Context, userID int, git
 
 // fetchAuthenticatedUser fetches the authenticated user's private data
 func (s *PrivateDataService) fetchAuthenticatedUser(ctx context.Context, token string) (*GitHubPrivateUser, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/user", nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("GitHub API error: %d - %s", resp.StatusCode, string(body))
-	}
-
-	var user GitHubPrivateUser
-	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
-		return nil, err
-	}
-
-	return &user, nil
+	return github.GetJSON[GitHubPrivateUser](ctx, s.client.WithToken(token), "/user")
 }
 
 // fetchEmails fetches the user's emails
 func (s *PrivateDataService) fetchEmails(ctx context.Context, token string) ([]GitHubEmail, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/user/emails", nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch emails: %d", resp.StatusCode)
-	}
-
 	var emails []GitHubEmail
-	if err := json.NewDecoder(resp.Body).Decode(&emails); err != nil {
-		return nil, err
+	if _, err := s.client.WithToken(token).Get(ctx, "/user/emails", &emails); err != nil {
+		return nil, fmt.Errorf("failed to fetch emails: %w", err)
 	}
-
 	return emails, nil
 }
 
 // fetchOrganizations fetches the user's organizations
 func (s *PrivateDataService) fetchOrganizations(ctx context.Context, token string) ([]GitHubOrg, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/user/orgs", nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch organizations: %d", resp.StatusCode)
-	}
-
 	var orgs []GitHubOrg
-	if err := json.NewDecoder(resp.Body).Decode(&orgs); err != nil {
-		return nil, err
+	if _, err := s.client.WithToken(token).Get(ctx, "/user/orgs", &orgs); err != nil {
+		return nil, fmt.Errorf("failed to fetch organizations: %w", err)
 	}
-
 	return orgs, nil
 }
 
 // fetchStarredCount fetches the count of starred repositories
 func (s *PrivateDataService) fetchStarredCount(ctx context.Context, token string) (int, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/user/starred?per_page=1", nil)
-	if err != nil {
-		return 0, err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return 0, err
-	}
-	defer resp.Body.Close()
-
-	// Parse Link header to get total count
-	return parseLinkHeaderCount(resp.Header.Get("Link")), nil
+	return s.fetchLinkCount(ctx, token, "/user/starred?per_page=1")
 }
 
 // fetchWatchingCount fetches the count of watched repositories
 func (s *PrivateDataService) fetchWatchingCount(ctx context.Context, token string) (int, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/user/subscriptions?per_page=1", nil)
-	if err != nil {
-		return 0, err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	return s.fetchLinkCount(ctx, token, "/user/subscriptions?per_page=1")
+}
 
-	resp, err := s.httpClient.Do(req)
+// fetchLinkCount counts items of a per_page=1 listing from its Link header
+func (s *PrivateDataService) fetchLinkCount(ctx context.Context, token, path string) (int, error) {
+	resp, err := s.client.WithToken(token).Get(ctx, path, nil)
 	if err != nil {
 		return 0, err
 	}
-	defer resp.Body.Close()
 
-	return parseLinkHeaderCount(resp.Header.Get("Link")), nil
+	// The last page number of a per_page=1 listing is its total count
+	return github.LastPage(resp.Header.Get("Link")), nil
 }
 
 // fetchSSHKeysCount fetches the count of SSH keys
 func (s *PrivateDataService) fetchSSHKeysCount(ctx context.Context, token string) (int, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/user/keys", nil)
-	if err != nil {
-		return 0, err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return 0, err
-	}
-	defer resp.Body.Close()
-
 	var keys []interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&keys); err != nil {
+	if _, err := s.client.WithToken(token).Get(ctx, "/user/keys", &keys); err != nil {
 		return 0, err
 	}
-
 	return len(keys), nil
 }
 
 // fetchGPGKeysCount fetches the count of GPG keys
 func (s *PrivateDataService) fetchGPGKeysCount(ctx context.Context, token string) (int, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/user/gpg_keys", nil)
-	if err != nil {
-		return 0, err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return 0, err
-	}
-	defer resp.Body.Close()
-
 	var keys []interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&keys); err != nil {
+	if _, err := s.client.WithToken(token).Get(ctx, "/user/gpg_keys", &keys); err != nil {
 		return 0, err
 	}
-
 	return len(keys), nil
 }
 
 // fetchRecentPrivateRepos fetches recent private repositories
 func (s *PrivateDataService) fetchRecentPrivateRepos(ctx context.Context, token string) ([]GitHubRepo, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/user/repos?type=private&sort=updated&per_page=10", nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch private repos: %d", resp.StatusCode)
-	}
-
 	var repos []GitHubRepo
-	if err := json.NewDecoder(resp.Body).Decode(&repos); err != nil {
-		return nil, err
+	if _, err := s.client.WithToken(token).Get(ctx, "/user/repos?type=private&sort=updated&per_page=10", &repos); err != nil {
+		return nil, fmt.Errorf("failed to fetch private repos: %w", err)
 	}
-
 	return repos, nil
 }
-
-// parseLinkHeaderCount parses the Link header to extract total page count
-func parseLinkHeaderCount(link string) int {
-	if link == "" {
-		return 0
-	}
-
-	// Simple parsing for the last page number
-	// Format: <url?page=2>; rel="next", <url?page=10>; rel="last"
-	// We want to extract 10 from page=10 in rel="last"
-
-	// Find rel="last"
-	lastIdx := -1
-	for i := len(link) - 1; i >= 0; i-- {
-		if link[i:] == `rel="last"` || (i+10 <= len(link) && link[i:i+10] == `rel="last"`) {
-			lastIdx = i
-			break
-		}
-	}
-
-	if lastIdx == -1 {
-		return 1 // No pagination, single page
-	}
-
-	// Find page= before rel="last"
-	for i := lastIdx; i >= 0; i-- {
-		if i+5 <= len(link) && link[i:i+5] == "page=" {
-			// Extract number
-			num := 0
-			for j := i + 5; j < len(link) && link[j] >= '0' && link[j] <= '9'; j++ {
-				num = num*10 + int(link[j]-'0')
-			}
-			return num
-		}
-	}
-
-	return 1
-}
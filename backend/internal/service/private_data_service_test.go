@@ -0,0 +1,51 @@
+// Package service provides tests for the private data service
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github-api/backend/internal/github"
+)
+
+func newTestPrivateDataService(t *testing.T, handler http.HandlerFunc) *PrivateDataService {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewPrivateDataService(nil, github.NewClient(server.URL, "shared", 5*time.Second))
+}
+
+func TestPrivateDataUsesUserToken(t *testing.T) {
+	svc := newTestPrivateDataService(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer user-token" {
+			t.Errorf("Expected the user's token, got %q", got)
+		}
+		w.Write([]byte(`[{"email":"a@example.com","primary":true,"verified":true},{"email":"b@example.com"}]`))
+	})
+
+	emails, err := svc.fetchEmails(context.Background(), "user-token")
+	if err != nil {
+		t.Fatalf("fetchEmails failed: %v", err)
+	}
+	if len(emails) != 2 || !emails[0].Primary {
+		t.Errorf("Unexpected emails %+v", emails)
+	}
+}
+
+func TestPrivateDataStarredCountFromLinkHeader(t *testing.T) {
+	svc := newTestPrivateDataService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", `<https://api.github.com/user/starred?per_page=1&page=2>; rel="next", <https://api.github.com/user/starred?per_page=1&page=42>; rel="last"`)
+		w.Write([]byte(`[{}]`))
+	})
+
+	count, err := svc.fetchStarredCount(context.Background(), "user-token")
+	if err != nil {
+		t.Fatalf("fetchStarredCount failed: %v", err)
+	}
+	if count != 42 {
+		t.Errorf("Expected 42 starred repos, got %d", count)
+	}
+}
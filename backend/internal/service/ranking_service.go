@@ -54,29 +54,33 @@ func CalculateUserScore(ranking *models.UserRanking) float64 {
 // FetchAndCalculateUserRanking fetches user data and calculates ranking
 func (s *RankingService) FetchAndCalculateUserRanking(ctx context.Context, username string) (*models.UserRanking, error) {
 	// Fetch user basic info
-	user, err := s.githubService.FetchUser(username)
+	user, err := s.githubService.FetchUser(ctx, username)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch user: %w", err)
 	}
 
 	// Fetch user repos to calculate stars and forks
-	repos, err := s.githubService.FetchUserRepos(username)
+	repos, err := s.githubService.FetchUserRepos(ctx, username)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch repos: %w", err)
 	}
 
+	if repos.Truncated {
+		log.Printf("⚠️ [Ranking] Repository list for %s truncated at %d repos", username, len(repos.Repos))
+	}
+
 	totalStars := 0
 	totalForks := 0
-	for _, repo := range repos {
+	for _, repo := range repos.Repos {
 		totalStars += repo.StargazersCount
 		totalForks += repo.ForksCount
 	}
 
 	// Fetch events to estimate contribution count
-	events, err := s.githubService.FetchUserEvents(username)
+	events, err := s.githubService.FetchUserEvents(ctx, username)
 	contributionCount := 0
 	if err == nil {
-		contributionCount = len(events)
+		contributionCount = len(events.Events)
 	}
 
 	ranking := &models.UserRanking{
@@ -169,7 +173,12 @@ func (s *RankingService) RefreshRankings(ctx context.Context, usernames []string
 		wg.Add(1)
 		go func(user string) {
 			defer wg.Done()
-			semaphore <- struct{}{}        // Acquire
+			select {
+			case semaphore <- struct{}{}: // Acquire
+			case <-ctx.Done():
+				errors <- fmt.Errorf("failed to update %s: %w", user, ctx.Err())
+				return
+			}
 			defer func() { <-semaphore }() // Release
 
 			if err := s.UpdateUserRanking(ctx, user); err != nil {
@@ -0,0 +1,247 @@
+// Package service provides repository analytics
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github-api/backend/internal/github"
+	"github-api/backend/internal/models"
+)
+
+// ErrRepoNotFound is returned when GitHub reports that a repository does not exist
+var ErrRepoNotFound = errors.New("repository not found")
+
+// repoSampleSize is how many of a repository's most recent commits, issues
+// and top contributors its analytics are computed from (one page of each)
+const repoSampleSize = 100
+
+// FetchRepoAnalytics fetches a repository together with its recent commits,
+// issues, languages and contributors, concurrently, and derives its metrics.
+// With a session token private repositories the user can access resolve
+// too, but only public repositories are cached. A listing that fails leaves
+// the document partial, and partial documents are not cached. Concurrent
+// calls for the same repository share a single fetch.
+func (s *GitHubService) FetchRepoAnalytics(ctx context.Context, owner, repo string) (models.RepoAnalytics, error) {
+	key := repoKey(owner, repo)
+	return s.analyticsFlight.DoContext(ctx, viewerKey(ctx, key), func(ctx context.Context) (models.RepoAnalytics, error) {
+		base := fmt.Sprintf("/repos/%s/%s", url.PathEscape(owner), url.PathEscape(repo))
+
+		var analytics models.RepoAnalytics
+		var issues []models.RepoIssue
+		var languages map[string]int64
+		fetches := map[string]func() error{
+			"repository": func() error {
+				_, err := s.client.Get(ctx, base, &analytics.Repo)
+				return err
+			},
+			"commits": func() error {
+				_, err := s.client.Get(ctx, fmt.Sprintf("%s/commits?per_page=%d", base, repoSampleSize), &analytics.Commits)
+				if github.StatusCode(err) == http.StatusConflict {
+					return nil // an empty repository has no history
+				}
+				return err
+			},
+			"issues": func() error {
+				_, err := s.client.Get(ctx, fmt.Sprintf("%s/issues?state=all&per_page=%d", base, repoSampleSize), &issues)
+				return err
+			},
+			"languages": func() (err error) {
+				languages, err = s.GetRepoLanguages(ctx, owner, repo, true)
+				return err
+			},
+			"contributors": func() error {
+				_, err := s.client.Get(ctx, fmt.Sprintf("%s/contributors?per_page=%d", base, repoSampleSize), &analytics.Contributors)
+				return err
+			},
+		}
+
+		errs := fanOut(fetches)
+		if err := ctx.Err(); err != nil {
+			return models.RepoAnalytics{}, err
+		}
+		if err := errs["repository"]; err != nil {
+			if errors.Is(err, github.ErrNotFound) {
+				return models.RepoAnalytics{}, ErrRepoNotFound
+			}
+			return models.RepoAnalytics{}, err
+		}
+		for name, err := range errs {
+			if err != nil {
+				log.Printf("⚠️ [Repo] %s unavailable for %s: %v", name, key, err)
+				analytics.Partial = true
+			}
+		}
+
+		// The issues listing includes pull requests
+		for _, issue := range issues {
+			if issue.PullRequest == nil {
+				analytics.Issues = append(analytics.Issues, issue)
+			}
+		}
+		analytics.Languages = languageShares(languages)
+		analytics.Metrics = models.RepoMetrics{
+			CommitCadence: commitCadence(analytics.Commits),
+			Issues:        issueRatio(analytics.Issues),
+			Contributors:  contributorShare(analytics.Contributors),
+		}
+
+		if !analytics.Partial && !analytics.Repo.Private {
+			s.analyticsCache.Set(key, analytics)
+		}
+		return analytics, nil
+	})
+}
+
+// GetRepoAnalytics gets a repository's analytics with caching
+func (s *GitHubService) GetRepoAnalytics(ctx context.Context, owner, repo string, useCache bool) (models.RepoAnalytics, error) {
+	if useCache {
+		if analytics, found := s.analyticsCache.Get(repoKey(owner, repo)); found {
+			return analytics, nil
+		}
+	}
+	return s.FetchRepoAnalytics(ctx, owner, repo)
+}
+
+// fanOut runs every fetch concurrently and returns their errors by name
+func fanOut(fetches map[string]func() error) map[string]error {
+	errs := make(map[string]error, len(fetches))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, fetch := range fetches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := fetch()
+			mu.Lock()
+			errs[name] = err
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+// viewerKey is the key of an in-flight repository fetch. Fetches made with a
+// session token may reach private repositories, so they are only shared
+// with callers holding the same account's token.
+func viewerKey(ctx context.Context, key string) string {
+	token := github.TokenFromContext(ctx)
+	if token == "" {
+		return key
+	}
+	if login := github.TokenLogin(ctx); login != "" {
+		return key + "#" + strings.ToLower(login)
+	}
+	return key + "#" + token
+}
+
+// publicRepo reports whether a result about the repository at base, read
+// with ctx, may be cached for everyone. Without a session token only public
+// repositories are reachable; with one, the repository is looked up.
+func (s *GitHubService) publicRepo(ctx context.Context, base string) bool {
+	if github.TokenFromContext(ctx) == "" {
+		return true
+	}
+	var details struct {
+		Private bool `json:"private"`
+	}
+	_, err := s.client.Get(ctx, base, &details)
+	return err == nil && !details.Private
+}
+
+// repoKey is the cache key of a repository; GitHub names are case-insensitive
+func repoKey(owner, repo string) string {
+	return strings.ToLower(owner + "/" + repo)
+}
+
+// commitCadence measures commits per week over the span of the sampled
+// commits (at least a week) and the median gap between consecutive commits
+func commitCadence(commits []models.RepoCommit) models.CommitCadence {
+	var dates []time.Time
+	for _, commit := range commits {
+		if date, err := time.Parse(time.RFC3339, commit.Commit.Author.Date); err == nil {
+			dates = append(dates, date)
+		}
+	}
+	cadence := models.CommitCadence{Sampled: len(dates)}
+	if len(dates) == 0 {
+		return cadence
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].After(dates[j]) })
+	cadence.LastCommitAt = &dates[0]
+
+	weeks := max(dates[0].Sub(dates[len(dates)-1]).Hours()/(24*7), 1)
+	cadence.CommitsPerWeek = math.Round(float64(len(dates))/weeks*10) / 10
+
+	if len(dates) > 1 {
+		gaps := make([]float64, len(dates)-1)
+		for i := range gaps {
+			gaps[i] = dates[i].Sub(dates[i+1]).Hours()
+		}
+		cadence.MedianGapHours = math.Round(median(gaps)*10) / 10
+	}
+	return cadence
+}
+
+// issueRatio counts open and closed issues
+func issueRatio(issues []models.RepoIssue) models.IssueRatio {
+	var ratio models.IssueRatio
+	for _, issue := range issues {
+		if issue.State == "open" {
+			ratio.Open++
+		} else {
+			ratio.Closed++
+		}
+	}
+	if total := ratio.Open + ratio.Closed; total > 0 {
+		ratio.OpenRatio = math.Round(float64(ratio.Open)/float64(total)*1000) / 1000
+	}
+	return ratio
+}
+
+// contributorShare computes the share of commits held by the top contributor
+// and the top five. GitHub lists contributors by commit count, largest first.
+func contributorShare(contributors []models.RepoContributor) models.ContributorShare {
+	share := models.ContributorShare{Contributors: len(contributors)}
+	var top, topFive int
+	for i, contributor := range contributors {
+		share.TotalContributions += contributor.Contributions
+		if i == 0 {
+			top = contributor.Contributions
+			share.TopContributor = contributor.Login
+		}
+		if i < 5 {
+			topFive += contributor.Contributions
+		}
+	}
+	if share.TotalContributions > 0 {
+		total := float64(share.TotalContributions)
+		share.TopShare = math.Round(float64(top)*1000/total) / 10
+		share.TopFiveShare = math.Round(float64(topFive)*1000/total) / 10
+	}
+	return share
+}
+
+// median returns the median of values, reordering them
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
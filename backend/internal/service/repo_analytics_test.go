@@ -0,0 +1,118 @@
+// Package service provides tests for repository analytics
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRepoAnalytics(t *testing.T) {
+	var contributorsDown atomic.Bool
+	svc, fx := newFixture(t, map[string]any{
+		"/repos/octocat/hello": `{"name":"hello","full_name":"octocat/hello","stargazers_count":42,"owner":{"login":"octocat"}}`,
+		"/repos/octocat/hello/commits": `[
+			{"sha":"c","commit":{"author":{"date":"2024-03-15T00:00:00Z"}}},
+			{"sha":"b","commit":{"author":{"date":"2024-03-08T00:00:00Z"}}},
+			{"sha":"a","commit":{"author":{"date":"2024-03-01T00:00:00Z"}}}
+		]`,
+		"/repos/octocat/hello/issues": `[
+			{"number":3,"state":"open"},
+			{"number":2,"state":"closed"},
+			{"number":1,"state":"closed"},
+			{"number":4,"state":"open","pull_request":{"merged_at":null}}
+		]`,
+		"/repos/octocat/hello/languages": `{"Go":300,"Shell":100}`,
+		"/repos/octocat/hello/contributors": func(w http.ResponseWriter, r *http.Request) {
+			if contributorsDown.Load() {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte(`[{"login":"octocat","contributions":60},{"login":"hubot","contributions":30},{"login":"monalisa","contributions":10}]`))
+		},
+	})
+
+	ctx := sessionContext()
+	analytics, err := svc.GetRepoAnalytics(ctx, "octocat", "hello", true)
+	if err != nil {
+		t.Fatalf("GetRepoAnalytics failed: %v", err)
+	}
+
+	if analytics.Repo.StargazersCount != 42 || len(analytics.Commits) != 3 || analytics.Partial {
+		t.Errorf("Unexpected analytics %+v", analytics)
+	}
+	if len(analytics.Issues) != 3 {
+		t.Errorf("Pull requests should be left out of issues, got %d", len(analytics.Issues))
+	}
+	if len(analytics.Languages) != 2 || analytics.Languages[0].Percent != 75 {
+		t.Errorf("Unexpected languages %v", analytics.Languages)
+	}
+	metrics := analytics.Metrics
+	if metrics.CommitCadence.CommitsPerWeek != 1.5 || metrics.CommitCadence.MedianGapHours != 168 {
+		t.Errorf("Unexpected commit cadence %+v", metrics.CommitCadence)
+	}
+	if metrics.Issues.Open != 1 || metrics.Issues.Closed != 2 || metrics.Issues.OpenRatio != 0.333 {
+		t.Errorf("Unexpected issue ratio %+v", metrics.Issues)
+	}
+	if metrics.Contributors.TopContributor != "octocat" || metrics.Contributors.TopShare != 60 || metrics.Contributors.TopFiveShare != 100 {
+		t.Errorf("Unexpected contributor share %+v", metrics.Contributors)
+	}
+
+	before := fx.calls.Load()
+	if _, err := svc.GetRepoAnalytics(ctx, "OctoCat", "Hello", true); err != nil {
+		t.Fatalf("GetRepoAnalytics failed: %v", err)
+	}
+	if fx.calls.Load() != before {
+		t.Errorf("Cached analytics should not call GitHub, got %d more calls", fx.calls.Load()-before)
+	}
+
+	contributorsDown.Store(true)
+	analytics, err = svc.GetRepoAnalytics(ctx, "octocat", "hello", false)
+	if err != nil {
+		t.Fatalf("A failed listing should not fail the lookup: %v", err)
+	}
+	if !analytics.Partial || len(analytics.Contributors) != 0 {
+		t.Errorf("Expected partial analytics without contributors, got %+v", analytics)
+	}
+	if cached, _ := svc.analyticsCache.Get("octocat/hello"); cached.Partial {
+		t.Error("Partial analytics must not replace the cached document")
+	}
+
+	if _, err := svc.GetRepoAnalytics(ctx, "octocat", "missing", true); !errors.Is(err, ErrRepoNotFound) {
+		t.Errorf("Expected ErrRepoNotFound, got %v", err)
+	}
+}
+
+func TestPrivateRepoAnalyticsNotShared(t *testing.T) {
+	svc, fx := newFixture(t, map[string]any{
+		// Only the signed-in user's token can see the repository
+		"/repos/acme/secret": func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(`{"name":"secret","full_name":"acme/secret","private":true}`))
+		},
+		"/repos/acme/secret/commits":      `[]`,
+		"/repos/acme/secret/issues":       `[]`,
+		"/repos/acme/secret/languages":    `{}`,
+		"/repos/acme/secret/contributors": `[]`,
+	})
+
+	analytics, err := svc.GetRepoAnalytics(sessionContext(), "acme", "secret", true)
+	if err != nil {
+		t.Fatalf("A private repository the user can access should resolve: %v", err)
+	}
+	if analytics.Repo.FullName != "acme/secret" || fx.authorization("/repos/acme/secret") != "Bearer user-token" {
+		t.Errorf("Expected the repository to be read with the session token, got %+v", analytics.Repo)
+	}
+	if _, found := svc.analyticsCache.Get("acme/secret"); found {
+		t.Error("Private repositories must not be cached")
+	}
+
+	if _, err := svc.GetRepoAnalytics(context.Background(), "acme", "secret", true); !errors.Is(err, ErrRepoNotFound) {
+		t.Errorf("Anonymous callers should not see the private repository, got %v", err)
+	}
+}
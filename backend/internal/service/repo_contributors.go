@@ -0,0 +1,256 @@
+// Package service provides contributor concentration analysis
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github-api/backend/internal/github"
+	"github-api/backend/internal/models"
+)
+
+// Contributor window bounds in days
+const (
+	DefaultContributorWindow = 90
+	MaxContributorWindow     = 365
+)
+
+// contributorStats is one entry of a repository's contributor statistics
+type contributorStats struct {
+	Total  int               `json:"total"`
+	Author *models.RepoOwner `json:"author"`
+}
+
+// FetchContributorConcentration measures how dependent a repository is on
+// few contributors over the last windowDays days. Commits since twice the
+// window are listed (up to the page cap) so that churn can compare the
+// window with the one before it, and GitHub's contributor statistics add the
+// all-time picture. When the listing stops at the page cap the previous
+// window is incomplete, so churn is left unknown. Private repositories and
+// results whose statistics are still being computed or whose commits were
+// truncated are not cached. Concurrent calls for the same repository and
+// window share a single fetch.
+func (s *GitHubService) FetchContributorConcentration(ctx context.Context, owner, repo string, windowDays int) (models.ContributorConcentration, error) {
+	key := windowKey(owner, repo, windowDays)
+	return s.concentrationFlight.DoContext(ctx, viewerKey(ctx, key), func(ctx context.Context) (models.ContributorConcentration, error) {
+		base := fmt.Sprintf("/repos/%s/%s", url.PathEscape(owner), url.PathEscape(repo))
+
+		now := timeNow().UTC()
+		since := now.AddDate(0, 0, -windowDays)
+		previousSince := now.AddDate(0, 0, -2*windowDays)
+
+		var commits []models.RepoCommit
+		var truncated bool
+		var rawStats json.RawMessage
+		var statsPending bool
+		fetches := map[string]func() error{
+			"commits": func() (err error) {
+				path := fmt.Sprintf("%s/commits?since=%s&per_page=%d", base, url.QueryEscape(previousSince.Format(time.RFC3339)), repoSampleSize)
+				commits, truncated, _, err = fetchAll[models.RepoCommit](ctx, s.client, path, github.Validators{}, s.config.MaxPages)
+				if github.StatusCode(err) == http.StatusConflict {
+					return nil // an empty repository has no history
+				}
+				return err
+			},
+			"stats": func() error {
+				resp, err := s.client.Get(ctx, base+"/stats/contributors", &rawStats)
+				if resp != nil && resp.StatusCode == http.StatusAccepted {
+					statsPending = true // GitHub is computing them; the body is empty
+					return nil
+				}
+				return err
+			},
+		}
+
+		errs := fanOut(fetches)
+		if err := ctx.Err(); err != nil {
+			return models.ContributorConcentration{}, err
+		}
+		if err := errs["commits"]; err != nil {
+			if errors.Is(err, github.ErrNotFound) {
+				return models.ContributorConcentration{}, ErrRepoNotFound
+			}
+			return models.ContributorConcentration{}, err
+		}
+
+		current := make(map[string]int)
+		previous := make(map[string]int)
+		for _, commit := range commits {
+			date, err := time.Parse(time.RFC3339, commit.Commit.Author.Date)
+			if err != nil || date.Before(previousSince) {
+				continue
+			}
+			author := commit.Commit.Author.Name
+			if commit.Author != nil {
+				author = commit.Author.Login
+			}
+			if date.Before(since) {
+				previous[author]++
+			} else {
+				current[author]++
+			}
+		}
+
+		report := models.ContributorConcentration{
+			Repo:         fmt.Sprintf("%s/%s", owner, repo),
+			WindowDays:   windowDays,
+			Since:        since,
+			Contributors: contributorActivity(current),
+			StatsPending: statsPending,
+			Truncated:    truncated,
+		}
+		if !truncated {
+			churn := maintainerChurn(previous, current)
+			report.Churn = &churn
+		}
+		for _, contributor := range report.Contributors {
+			report.Commits += contributor.Commits
+		}
+		report.BusFactor = busFactor(report.Contributors)
+		report.Gini = giniCoefficient(report.Contributors)
+		if len(report.Contributors) > 0 {
+			report.TopShare = report.Contributors[0].Share
+		}
+
+		if err := errs["stats"]; err != nil {
+			log.Printf("⚠️ [Contributors] stats unavailable for %s: %v", key, err)
+		} else if !statsPending && len(rawStats) > 0 {
+			var stats []contributorStats
+			if err := json.Unmarshal(rawStats, &stats); err != nil {
+				log.Printf("⚠️ [Contributors] unreadable stats for %s: %v", key, err)
+			} else {
+				totals := make(map[string]int, len(stats))
+				for _, stat := range stats {
+					if stat.Author != nil {
+						totals[stat.Author.Login] += stat.Total
+					}
+				}
+				report.AllTime = contributorActivity(totals)
+				report.AllTimeBusFactor = busFactor(report.AllTime)
+			}
+		}
+
+		if !statsPending && !truncated && errs["stats"] == nil && s.publicRepo(ctx, base) {
+			s.concentrationCache.Set(key, report)
+		}
+		return report, nil
+	})
+}
+
+// GetContributorConcentration gets a repository's contributor concentration
+// over a window with caching
+func (s *GitHubService) GetContributorConcentration(ctx context.Context, owner, repo string, windowDays int, useCache bool) (models.ContributorConcentration, error) {
+	if useCache {
+		if report, found := s.concentrationCache.Get(windowKey(owner, repo, windowDays)); found {
+			return report, nil
+		}
+	}
+	return s.FetchContributorConcentration(ctx, owner, repo, windowDays)
+}
+
+// windowKey is the cache key of a repository's report over a window
+func windowKey(owner, repo string, windowDays int) string {
+	return fmt.Sprintf("%s@%d", repoKey(owner, repo), windowDays)
+}
+
+// contributorActivity lists contributors by commits, most first, with their
+// percentage share of all the commits counted
+func contributorActivity(commits map[string]int) []models.ContributorActivity {
+	total := 0
+	for _, count := range commits {
+		total += count
+	}
+
+	activity := make([]models.ContributorActivity, 0, len(commits))
+	for login, count := range commits {
+		if count == 0 {
+			continue
+		}
+		share := math.Round(float64(count)*1000/float64(total)) / 10
+		activity = append(activity, models.ContributorActivity{Login: login, Commits: count, Share: share})
+	}
+	sort.Slice(activity, func(i, j int) bool {
+		if activity[i].Commits != activity[j].Commits {
+			return activity[i].Commits > activity[j].Commits
+		}
+		return activity[i].Login < activity[j].Login
+	})
+	return activity
+}
+
+// busFactor returns the fewest contributors who together authored at least
+// half of the commits; activity must be sorted by commits, most first
+func busFactor(activity []models.ContributorActivity) int {
+	total := 0
+	for _, contributor := range activity {
+		total += contributor.Commits
+	}
+
+	covered := 0
+	for i, contributor := range activity {
+		covered += contributor.Commits
+		if covered*2 >= total {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// giniCoefficient measures the inequality of commit counts between
+// contributors: 0 when everyone committed equally, approaching 1 when one
+// contributor authored nearly everything
+func giniCoefficient(activity []models.ContributorActivity) float64 {
+	n := len(activity)
+	if n < 2 {
+		return 0
+	}
+
+	// Sum of (2i - n - 1) * x_i over counts in ascending order, i from 1
+	var weighted, total float64
+	for i := range activity {
+		count := float64(activity[n-1-i].Commits)
+		weighted += float64(2*(i+1)-n-1) * count
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+	return math.Round(weighted/(float64(n)*total)*1000) / 1000
+}
+
+// maintainerChurn compares the contributors of two consecutive windows
+func maintainerChurn(previous, current map[string]int) models.MaintainerChurn {
+	churn := models.MaintainerChurn{
+		Previous: len(previous),
+		Current:  len(current),
+		Joined:   []string{},
+		Left:     []string{},
+	}
+	for login := range current {
+		if _, ok := previous[login]; ok {
+			churn.Retained++
+		} else {
+			churn.Joined = append(churn.Joined, login)
+		}
+	}
+	for login := range previous {
+		if _, ok := current[login]; !ok {
+			churn.Left = append(churn.Left, login)
+		}
+	}
+	sort.Strings(churn.Joined)
+	sort.Strings(churn.Left)
+
+	if churn.Previous > 0 {
+		churn.ChurnRate = math.Round(float64(len(churn.Left))/float64(churn.Previous)*1000) / 1000
+	}
+	return churn
+}
@@ -0,0 +1,114 @@
+// Package service provides tests for contributor concentration analysis
+package service
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestContributorConcentration(t *testing.T) {
+	defer func(now func() time.Time) { timeNow = now }(timeNow)
+	timeNow = func() time.Time { return time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC) }
+
+	var statsReady atomic.Bool
+	svc, _ := newFixture(t, map[string]any{
+		"/repos/octocat/hello": `{"full_name":"octocat/hello","private":false}`,
+		"/repos/octocat/hello/commits": func(w http.ResponseWriter, r *http.Request) {
+			if since := r.URL.Query().Get("since"); since != "2024-05-01T12:00:00Z" {
+				t.Errorf("Expected commits since two windows ago, got %q", since)
+			}
+			commit := func(login, name, date string) string {
+				author := "null"
+				if login != "" {
+					author = `{"login":"` + login + `"}`
+				}
+				return `{"commit":{"author":{"name":"` + name + `","date":"` + date + `"}},"author":` + author + `}`
+			}
+			var commits []string
+			for i := 0; i < 6; i++ {
+				commits = append(commits, commit("alice", "Alice", "2024-06-20T00:00:00Z"))
+			}
+			commits = append(commits,
+				commit("bob", "Bob", "2024-06-10T00:00:00Z"),
+				commit("bob", "Bob", "2024-06-09T00:00:00Z"),
+				commit("", "Carol Dev", "2024-06-05T00:00:00Z"),
+				commit("dave", "Dave", "2024-06-01T00:00:00Z"),
+				commit("alice", "Alice", "2024-05-20T00:00:00Z"),
+				commit("alice", "Alice", "2024-05-10T00:00:00Z"),
+				commit("erin", "Erin", "2024-05-05T00:00:00Z"),
+			)
+			w.Write([]byte("[" + strings.Join(commits, ",") + "]"))
+		},
+		"/repos/octocat/hello/stats/contributors": func(w http.ResponseWriter, r *http.Request) {
+			if !statsReady.Load() {
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+			w.Write([]byte(`[{"total":10,"author":{"login":"bob"}},{"total":90,"author":{"login":"alice"}}]`))
+		},
+	})
+
+	ctx := sessionContext()
+	report, err := svc.GetContributorConcentration(ctx, "octocat", "hello", 30, true)
+	if err != nil {
+		t.Fatalf("GetContributorConcentration failed: %v", err)
+	}
+
+	if report.Commits != 10 || len(report.Contributors) != 4 || report.Contributors[0].Login != "alice" {
+		t.Errorf("Unexpected contributors %+v", report.Contributors)
+	}
+	if report.BusFactor != 1 || report.Gini != 0.4 || report.TopShare != 60 {
+		t.Errorf("Expected bus factor 1, Gini 0.4 and top share 60, got %d, %v and %v", report.BusFactor, report.Gini, report.TopShare)
+	}
+	churn := report.Churn
+	if churn == nil || churn.Previous != 2 || churn.Retained != 1 || len(churn.Joined) != 3 || len(churn.Left) != 1 || churn.Left[0] != "erin" || churn.ChurnRate != 0.5 {
+		t.Errorf("Unexpected churn %+v", churn)
+	}
+	if !report.StatsPending || report.AllTime != nil {
+		t.Errorf("Expected pending statistics, got %+v", report)
+	}
+	if _, found := svc.concentrationCache.Get("octocat/hello@30"); found {
+		t.Error("Reports with pending statistics must not be cached")
+	}
+
+	statsReady.Store(true)
+	report, err = svc.GetContributorConcentration(ctx, "octocat", "hello", 30, true)
+	if err != nil {
+		t.Fatalf("GetContributorConcentration failed: %v", err)
+	}
+	if report.StatsPending || len(report.AllTime) != 2 || report.AllTime[0].Share != 90 || report.AllTimeBusFactor != 1 {
+		t.Errorf("Unexpected all-time statistics %+v", report.AllTime)
+	}
+	if _, found := svc.concentrationCache.Get("octocat/hello@30"); !found {
+		t.Error("Complete reports should be cached per window")
+	}
+}
+
+func TestContributorChurnUnknownWhenTruncated(t *testing.T) {
+	defer func(now func() time.Time) { timeNow = now }(timeNow)
+	timeNow = func() time.Time { return time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC) }
+
+	svc, _ := newFixture(t, map[string]any{
+		// The listing never ends, so only the cap stops it within the window
+		"/repos/octocat/hello/commits": func(w http.ResponseWriter, r *http.Request) {
+			nextPage(w, r)
+			w.Write([]byte(`[{"commit":{"author":{"name":"Alice","date":"2024-06-20T00:00:00Z"}},"author":{"login":"alice"}}]`))
+		},
+		"/repos/octocat/hello/stats/contributors": `[]`,
+	})
+	svc.config.MaxPages = 2
+
+	report, err := svc.GetContributorConcentration(sessionContext(), "octocat", "hello", 30, true)
+	if err != nil {
+		t.Fatalf("GetContributorConcentration failed: %v", err)
+	}
+	if !report.Truncated || report.Churn != nil || report.Commits != 2 {
+		t.Errorf("Expected truncated commits with unknown churn, got %+v", report)
+	}
+	if _, found := svc.concentrationCache.Get("octocat/hello@30"); found {
+		t.Error("Truncated reports must not be cached")
+	}
+}
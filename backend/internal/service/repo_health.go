@@ -0,0 +1,360 @@
+// Package service provides repository health scoring
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github-api/backend/internal/github"
+	"github-api/backend/internal/models"
+)
+
+// Health check thresholds
+const (
+	freshCommitDays   = 30
+	staleCommitDays   = 90
+	fastResponseHours = 72
+	slowResponseHours = 7 * 24
+	freshPRDays       = 30
+	stalePRDays       = 90
+)
+
+// communityProfile lists the community health files GitHub detected
+type communityProfile struct {
+	Files map[string]*struct {
+		Name string `json:"name"`
+	} `json:"files"`
+}
+
+// has reports whether GitHub detected any of the named files
+func (p communityProfile) has(names ...string) bool {
+	for _, name := range names {
+		if p.Files[name] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// release is a published release of a repository
+type release struct {
+	TagName     string `json:"tag_name"`
+	PublishedAt string `json:"published_at"`
+}
+
+// pullRequest is an open pull request
+type pullRequest struct {
+	CreatedAt string `json:"created_at"`
+}
+
+// issueComment is a comment from a repository's issue comments listing
+type issueComment struct {
+	IssueURL  string           `json:"issue_url"`
+	CreatedAt string           `json:"created_at"`
+	User      models.RepoOwner `json:"user"`
+}
+
+// FetchRepoHealth scores a repository against a checklist of community
+// files, CI, releases, commit recency, issue responsiveness, open pull
+// request age and metadata. Checks without data, such as issue response in
+// a repository without issues, are left unscored. It builds on the
+// repository analytics and, like them, caches neither private repositories
+// nor partial results. Concurrent calls for the same repository share a
+// single fetch.
+func (s *GitHubService) FetchRepoHealth(ctx context.Context, owner, repo string) (models.RepoHealth, error) {
+	key := repoKey(owner, repo)
+	return s.healthFlight.DoContext(ctx, viewerKey(ctx, key), func(ctx context.Context) (models.RepoHealth, error) {
+		base := fmt.Sprintf("/repos/%s/%s", url.PathEscape(owner), url.PathEscape(repo))
+
+		var analytics models.RepoAnalytics
+		var community communityProfile
+		var workflows []struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		}
+		var releases []release
+		var openIssues []models.RepoIssue
+		var comments []issueComment
+		var pulls []pullRequest
+		fetches := map[string]func() error{
+			"analytics": func() (err error) {
+				analytics, err = s.GetRepoAnalytics(ctx, owner, repo, true)
+				return err
+			},
+			"community": func() error {
+				_, err := s.client.Get(ctx, base+"/community/profile", &community)
+				return err
+			},
+			"workflows": func() error {
+				_, err := s.client.Get(ctx, base+"/contents/.github/workflows", &workflows)
+				if github.StatusCode(err) == http.StatusNotFound {
+					return nil // no workflows directory
+				}
+				return err
+			},
+			"releases": func() error {
+				_, err := s.client.Get(ctx, base+"/releases?per_page=1", &releases)
+				return err
+			},
+			"open_issues": func() error {
+				_, err := s.client.Get(ctx, fmt.Sprintf("%s/issues?state=open&sort=created&direction=asc&per_page=%d", base, repoSampleSize), &openIssues)
+				return err
+			},
+			"comments": func() error {
+				_, err := s.client.Get(ctx, fmt.Sprintf("%s/issues/comments?sort=created&direction=desc&per_page=%d", base, repoSampleSize), &comments)
+				return err
+			},
+			"pulls": func() error {
+				_, err := s.client.Get(ctx, fmt.Sprintf("%s/pulls?state=open&per_page=%d", base, repoSampleSize), &pulls)
+				return err
+			},
+		}
+
+		errs := fanOut(fetches)
+		if err := ctx.Err(); err != nil {
+			return models.RepoHealth{}, err
+		}
+		if err := errs["analytics"]; err != nil {
+			return models.RepoHealth{}, err
+		}
+
+		health := models.RepoHealth{Repo: analytics.Repo.FullName, Partial: analytics.Partial}
+		for name, err := range errs {
+			if err != nil {
+				log.Printf("⚠️ [Health] %s unavailable for %s: %v", name, key, err)
+				health.Partial = true
+			}
+		}
+
+		now := timeNow()
+		details := analytics.Repo
+		hasCommunity := errs["community"] == nil
+		license := hasCommunity && community.has("license") || details.License != nil
+
+		ciFiles := 0
+		for _, file := range workflows {
+			if file.Type == "file" && (strings.HasSuffix(file.Name, ".yml") || strings.HasSuffix(file.Name, ".yaml")) {
+				ciFiles++
+			}
+		}
+
+		health.Checks = []models.HealthCheck{
+			presenceCheck("readme", "README", 10, hasCommunity && community.has("readme")),
+			presenceCheck("license", "LICENSE", 10, license),
+			presenceCheck("contributing", "CONTRIBUTING guide", 5, hasCommunity && community.has("contributing")),
+			presenceCheck("code_of_conduct", "Code of conduct", 5, hasCommunity && community.has("code_of_conduct", "code_of_conduct_file")),
+			countCheck("ci", "CI workflows", 15, ciFiles, "workflow"),
+			releaseCheck(releases),
+			commitRecencyCheck(analytics.Metrics.CommitCadence.LastCommitAt, now),
+			issueResponseCheck(analytics.Issues, openIssues, comments, now),
+			pullRequestAgeCheck(pulls, now),
+			presenceCheck("description", "Description", 5, details.Description != ""),
+			countCheck("topics", "Topics", 5, len(details.Topics), "topic"),
+		}
+		for _, check := range health.Checks {
+			health.Score += check.Score
+			health.MaxScore += check.MaxScore
+		}
+
+		if !health.Partial && !analytics.Repo.Private {
+			s.healthCache.Set(key, health)
+		}
+		return health, nil
+	})
+}
+
+// GetRepoHealth gets a repository's health checklist with caching
+func (s *GitHubService) GetRepoHealth(ctx context.Context, owner, repo string, useCache bool) (models.RepoHealth, error) {
+	if useCache {
+		if health, found := s.healthCache.Get(repoKey(owner, repo)); found {
+			return health, nil
+		}
+	}
+	return s.FetchRepoHealth(ctx, owner, repo)
+}
+
+// newCheck scores a check, passing it when it earned every point
+func newCheck(id, label string, score, maxScore int, detail string) models.HealthCheck {
+	return models.HealthCheck{ID: id, Label: label, Passed: score == maxScore, Score: score, MaxScore: maxScore, Detail: detail}
+}
+
+// unscoredCheck records a check there was nothing to judge by
+func unscoredCheck(id, label, detail string) models.HealthCheck {
+	return models.HealthCheck{ID: id, Label: label, Unscored: true, Detail: detail}
+}
+
+// presenceCheck awards every point when something is present
+func presenceCheck(id, label string, maxScore int, present bool) models.HealthCheck {
+	if present {
+		return newCheck(id, label, maxScore, maxScore, "present")
+	}
+	return newCheck(id, label, 0, maxScore, "missing")
+}
+
+// countCheck awards every point when there is at least one of something
+func countCheck(id, label string, maxScore, count int, noun string) models.HealthCheck {
+	if count == 0 {
+		return newCheck(id, label, 0, maxScore, "no "+noun+"s")
+	}
+	if count == 1 {
+		return newCheck(id, label, maxScore, maxScore, "1 "+noun)
+	}
+	return newCheck(id, label, maxScore, maxScore, fmt.Sprintf("%d %ss", count, noun))
+}
+
+// releaseCheck passes repositories that have published a release
+func releaseCheck(releases []release) models.HealthCheck {
+	if len(releases) == 0 {
+		return newCheck("releases", "Releases", 0, 10, "no releases")
+	}
+	detail := "latest " + releases[0].TagName
+	if published, err := time.Parse(time.RFC3339, releases[0].PublishedAt); err == nil {
+		detail += " published " + published.Format("2006-01-02")
+	}
+	return newCheck("releases", "Releases", 10, 10, detail)
+}
+
+// commitRecencyCheck awards every point for a commit in the last 30 days and
+// half for one in the last 90
+func commitRecencyCheck(lastCommit *time.Time, now time.Time) models.HealthCheck {
+	const maxScore = 15
+	if lastCommit == nil {
+		return newCheck("recent_commits", "Recent commits", 0, maxScore, "no commits")
+	}
+
+	days := int(now.Sub(*lastCommit).Hours() / 24)
+	detail := fmt.Sprintf("last commit %d days ago", days)
+	switch {
+	case days <= freshCommitDays:
+		return newCheck("recent_commits", "Recent commits", maxScore, maxScore, detail)
+	case days <= staleCommitDays:
+		return newCheck("recent_commits", "Recent commits", maxScore/2, maxScore, detail)
+	}
+	return newCheck("recent_commits", "Recent commits", 0, maxScore, detail)
+}
+
+// issueResponseCheck scores the median time from an issue being opened to
+// its first comment by someone other than its author. It samples the most
+// recent issues and the oldest open ones, answered within the sampled
+// comments; an open issue nobody commented on counts as waiting since it was
+// opened, so a backlog of old unanswered issues weighs on the score. Every
+// point is awarded for a median within 3 days and half within a week.
+// Repositories without issues are not scored.
+func issueResponseCheck(recent, open []models.RepoIssue, comments []issueComment, now time.Time) models.HealthCheck {
+	const maxScore = 10
+	issues := sampledIssues(recent, open)
+	if len(issues) == 0 {
+		return unscoredCheck("issue_response", "Issue response time", "no issues")
+	}
+
+	firstResponse := firstResponses(issues, comments)
+	var hours []float64
+	waiting := 0
+	for _, issue := range issues {
+		opened, err := time.Parse(time.RFC3339, issue.CreatedAt)
+		if err != nil {
+			continue
+		}
+		if first, ok := firstResponse[issue.Number]; ok {
+			hours = append(hours, first.Sub(opened).Hours())
+		} else if issue.State == "open" && issue.Comments == 0 {
+			hours = append(hours, now.Sub(opened).Hours())
+			waiting++
+		}
+	}
+	if len(hours) == 0 {
+		return newCheck("issue_response", "Issue response time", 0, maxScore, fmt.Sprintf("none of the %d sampled issues answered", len(issues)))
+	}
+
+	medianHours := median(hours)
+	detail := fmt.Sprintf("median first response %.1f hours over %d issues", medianHours, len(hours))
+	if waiting > 0 {
+		detail += fmt.Sprintf(", %d open without a reply", waiting)
+	}
+	switch {
+	case medianHours <= fastResponseHours:
+		return newCheck("issue_response", "Issue response time", maxScore, maxScore, detail)
+	case medianHours <= slowResponseHours:
+		return newCheck("issue_response", "Issue response time", maxScore/2, maxScore, detail)
+	}
+	return newCheck("issue_response", "Issue response time", 0, maxScore, detail)
+}
+
+// sampledIssues merges issue listings, leaving out pull requests and issues
+// listed twice
+func sampledIssues(listings ...[]models.RepoIssue) []models.RepoIssue {
+	seen := make(map[int]bool)
+	var issues []models.RepoIssue
+	for _, listing := range listings {
+		for _, issue := range listing {
+			if issue.PullRequest != nil || seen[issue.Number] {
+				continue
+			}
+			seen[issue.Number] = true
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// firstResponses maps the number of each issue to the time of its earliest
+// comment by someone other than its author. Bots are not a response.
+func firstResponses(issues []models.RepoIssue, comments []issueComment) map[int]time.Time {
+	firstResponse := make(map[int]time.Time)
+	authors := make(map[int]string, len(issues))
+	for _, issue := range issues {
+		authors[issue.Number] = issue.User.Login
+	}
+	for _, comment := range comments {
+		number, err := strconv.Atoi(comment.IssueURL[strings.LastIndex(comment.IssueURL, "/")+1:])
+		author, sampled := authors[number]
+		if err != nil || !sampled || comment.User.Login == author || isBot(comment.User.Login) {
+			continue
+		}
+		created, err := time.Parse(time.RFC3339, comment.CreatedAt)
+		if err != nil {
+			continue
+		}
+		if first, ok := firstResponse[number]; !ok || created.Before(first) {
+			firstResponse[number] = created
+		}
+	}
+	return firstResponse
+}
+
+// isBot reports whether a login belongs to a GitHub App
+func isBot(login string) bool {
+	return strings.HasSuffix(login, "[bot]")
+}
+
+// pullRequestAgeCheck scores the median age of open pull requests: every
+// point within 30 days and half within 90. Without open pull requests there
+// is nothing to score.
+func pullRequestAgeCheck(pulls []pullRequest, now time.Time) models.HealthCheck {
+	const maxScore = 10
+	var ages []float64
+	for _, pull := range pulls {
+		if created, err := time.Parse(time.RFC3339, pull.CreatedAt); err == nil {
+			ages = append(ages, now.Sub(created).Hours()/24)
+		}
+	}
+	if len(ages) == 0 {
+		return unscoredCheck("open_pr_age", "Open pull request age", "no open pull requests")
+	}
+
+	medianDays := median(ages)
+	detail := fmt.Sprintf("%d open, median age %.0f days", len(ages), medianDays)
+	switch {
+	case medianDays <= freshPRDays:
+		return newCheck("open_pr_age", "Open pull request age", maxScore, maxScore, detail)
+	case medianDays <= stalePRDays:
+		return newCheck("open_pr_age", "Open pull request age", maxScore/2, maxScore, detail)
+	}
+	return newCheck("open_pr_age", "Open pull request age", 0, maxScore, detail)
+}
@@ -0,0 +1,100 @@
+// Package service provides tests for repository health scoring
+package service
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github-api/backend/internal/models"
+)
+
+func TestRepoHealth(t *testing.T) {
+	defer func(now func() time.Time) { timeNow = now }(timeNow)
+	timeNow = func() time.Time { return time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC) }
+
+	svc, _ := newFixture(t, map[string]any{
+		"/repos/octocat/hello":         `{"full_name":"octocat/hello","description":"Says hello","topics":[]}`,
+		"/repos/octocat/hello/commits": `[{"sha":"a","commit":{"author":{"date":"2024-01-20T00:00:00Z"}}}]`,
+		"/repos/octocat/hello/issues": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("state") == "open" {
+				// The oldest open issues: one nobody answered and one also listed as recent
+				w.Write([]byte(`[
+					{"number":0,"state":"open","comments":0,"created_at":"2024-01-01T00:00:00Z","user":{"login":"hubot"}},
+					{"number":2,"state":"open","comments":1,"created_at":"2024-03-10T00:00:00Z","user":{"login":"hubot"}}
+				]`))
+				return
+			}
+			w.Write([]byte(`[
+				{"number":2,"state":"open","comments":1,"created_at":"2024-03-10T00:00:00Z","user":{"login":"hubot"}},
+				{"number":1,"state":"closed","comments":2,"created_at":"2024-03-01T00:00:00Z","user":{"login":"monalisa"}}
+			]`))
+		},
+		"/repos/octocat/hello/issues/comments": `[
+			{"issue_url":"https://api.github.com/repos/octocat/hello/issues/2","created_at":"2024-03-10T12:00:00Z","user":{"login":"octocat"}},
+			{"issue_url":"https://api.github.com/repos/octocat/hello/issues/1","created_at":"2024-03-01T10:00:00Z","user":{"login":"octocat"}},
+			{"issue_url":"https://api.github.com/repos/octocat/hello/issues/1","created_at":"2024-03-01T01:00:00Z","user":{"login":"monalisa"}}
+		]`,
+		"/repos/octocat/hello/community/profile":          `{"files":{"readme":{"name":"README.md"},"license":null,"contributing":null,"code_of_conduct":null}}`,
+		"/repos/octocat/hello/contents/.github/workflows": `[{"name":"ci.yml","type":"file"},{"name":"README.md","type":"file"}]`,
+		"/repos/octocat/hello/releases":                   `[]`,
+		"/repos/octocat/hello/pulls":                      `[{"created_at":"2024-01-01T00:00:00Z"}]`,
+		"/repos/octocat/hello/languages":                  `{}`,
+		"/repos/octocat/hello/contributors":               `[]`,
+	})
+
+	health, err := svc.GetRepoHealth(sessionContext(), "octocat", "hello", true)
+	if err != nil {
+		t.Fatalf("GetRepoHealth failed: %v", err)
+	}
+
+	scores := make(map[string]int)
+	for _, check := range health.Checks {
+		scores[check.ID] = check.Score
+	}
+	want := map[string]int{
+		"readme": 10, "license": 0, "contributing": 0, "code_of_conduct": 0,
+		"ci": 15, "releases": 0, "recent_commits": 7, "issue_response": 10,
+		"open_pr_age": 5, "description": 5, "topics": 0,
+	}
+	for id, score := range want {
+		if scores[id] != score {
+			t.Errorf("%s: expected %d points, got %d", id, score, scores[id])
+		}
+	}
+	if health.Score != 52 || health.MaxScore != 100 || health.Partial {
+		t.Errorf("Expected 52/100, got %d/%d (partial %v)", health.Score, health.MaxScore, health.Partial)
+	}
+	for _, check := range health.Checks {
+		if check.ID == "issue_response" && !strings.HasSuffix(check.Detail, "over 3 issues, 1 open without a reply") {
+			t.Errorf("The old unanswered issue should count as waiting, got %q", check.Detail)
+		}
+	}
+}
+
+func TestIssueResponseCheck(t *testing.T) {
+	now := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+	issue := func(number int, state, created string) models.RepoIssue {
+		return models.RepoIssue{Number: number, State: state, CreatedAt: created, User: models.RepoOwner{Login: "hubot"}}
+	}
+	answered := issue(3, "closed", "2024-03-19T00:00:00Z")
+	comments := []issueComment{{IssueURL: "https://api.github.com/repos/octocat/hello/issues/3", CreatedAt: "2024-03-19T01:00:00Z", User: models.RepoOwner{Login: "octocat"}}}
+
+	if check := issueResponseCheck(nil, nil, nil, now); !check.Unscored || check.MaxScore != 0 || check.Passed {
+		t.Errorf("A repository without issues should not be scored, got %+v", check)
+	}
+	if check := issueResponseCheck([]models.RepoIssue{answered}, nil, comments, now); check.Score != 10 {
+		t.Errorf("Expected a quick answer to earn every point, got %+v", check)
+	}
+
+	// Old open issues nobody answered outweigh the recent answer
+	backlog := []models.RepoIssue{issue(1, "open", "2023-01-01T00:00:00Z"), issue(2, "open", "2023-06-01T00:00:00Z")}
+	if check := issueResponseCheck([]models.RepoIssue{answered}, backlog, comments, now); check.Score != 0 || check.Unscored {
+		t.Errorf("Expected the unanswered backlog to score nothing, got %+v", check)
+	}
+
+	if check := pullRequestAgeCheck(nil, now); !check.Unscored || check.MaxScore != 0 {
+		t.Errorf("A repository without open pull requests should not be scored, got %+v", check)
+	}
+}
@@ -0,0 +1,318 @@
+// Package service provides issue and pull request velocity metrics
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github-api/backend/internal/github"
+	"github-api/backend/internal/models"
+)
+
+// Velocity window bounds in weeks
+const (
+	DefaultVelocityWeeks = 12
+	MaxVelocityWeeks     = 52
+)
+
+const (
+	// staleIssueDays is how long an open issue goes without updates before it
+	// counts as stale
+	staleIssueDays = 30
+
+	// reviewSampleSize bounds the pull requests whose reviews are fetched,
+	// one call each, to measure review turnaround
+	reviewSampleSize = 30
+)
+
+// pullReview is a review submitted on a pull request
+type pullReview struct {
+	State       string           `json:"state"`
+	SubmittedAt string           `json:"submitted_at"`
+	User        models.RepoOwner `json:"user"`
+}
+
+// FetchRepoVelocity measures how quickly a repository responds to, reviews,
+// closes and merges issues and pull requests over the last weeks weeks. It
+// pages through the issues and pull requests updated in the window and their
+// comments (up to the page cap), fetches the reviews of the most recent pull
+// requests and counts stale issues with the search API. Private
+// repositories, partial results and listings cut short by the page cap are
+// not cached. Concurrent calls for the same repository and window share a
+// single fetch.
+func (s *GitHubService) FetchRepoVelocity(ctx context.Context, owner, repo string, weeks int) (models.RepoVelocity, error) {
+	key := windowKey(owner, repo, weeks)
+	return s.velocityFlight.DoContext(ctx, viewerKey(ctx, key), func(ctx context.Context) (models.RepoVelocity, error) {
+		base := fmt.Sprintf("/repos/%s/%s", url.PathEscape(owner), url.PathEscape(repo))
+
+		now := timeNow().UTC()
+		start := weekStart(now).AddDate(0, 0, -7*(weeks-1))
+		since := url.QueryEscape(start.Format(time.RFC3339))
+
+		var items []models.RepoIssue
+		var comments []issueComment
+		var itemsTruncated, commentsTruncated bool
+		var stale struct {
+			TotalCount int `json:"total_count"`
+		}
+		fetches := map[string]func() error{
+			"issues": func() (err error) {
+				path := fmt.Sprintf("%s/issues?state=all&since=%s&per_page=%d", base, since, repoSampleSize)
+				items, itemsTruncated, _, err = fetchAll[models.RepoIssue](ctx, s.client, path, github.Validators{}, s.config.MaxPages)
+				return err
+			},
+			"comments": func() (err error) {
+				path := fmt.Sprintf("%s/issues/comments?since=%s&sort=created&direction=asc&per_page=%d", base, since, repoSampleSize)
+				comments, commentsTruncated, _, err = fetchAll[issueComment](ctx, s.client, path, github.Validators{}, s.config.MaxPages)
+				return err
+			},
+			"stale": func() error {
+				query := fmt.Sprintf("repo:%s/%s is:issue is:open updated:<%s", owner, repo, now.AddDate(0, 0, -staleIssueDays).Format("2006-01-02"))
+				_, err := s.client.Get(ctx, "/search/issues?per_page=1&q="+url.QueryEscape(query), &stale)
+				return err
+			},
+		}
+
+		errs := fanOut(fetches)
+		if err := ctx.Err(); err != nil {
+			return models.RepoVelocity{}, err
+		}
+		if err := errs["issues"]; err != nil {
+			if errors.Is(err, github.ErrNotFound) {
+				return models.RepoVelocity{}, ErrRepoNotFound
+			}
+			return models.RepoVelocity{}, err
+		}
+
+		velocity := models.RepoVelocity{
+			Repo:      fmt.Sprintf("%s/%s", owner, repo),
+			Weeks:     weeks,
+			Since:     start,
+			Truncated: itemsTruncated || commentsTruncated,
+		}
+		for name, err := range errs {
+			if err != nil {
+				log.Printf("⚠️ [Velocity] %s unavailable for %s: %v", name, key, err)
+				velocity.Partial = true
+			}
+		}
+
+		reviews, err := s.firstReviews(ctx, base, items, start)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return models.RepoVelocity{}, ctxErr
+			}
+			log.Printf("⚠️ [Velocity] reviews unavailable for %s: %v", key, err)
+			velocity.Partial = true
+		}
+
+		velocity.Series, velocity.Summary = velocitySeries(items, firstResponses(items, comments), reviews, start, weeks)
+		velocity.Summary.Reviewed = len(reviews)
+		velocity.Summary.StaleIssues = stale.TotalCount
+		velocity.Summary.StaleDays = staleIssueDays
+
+		if !velocity.Partial && !velocity.Truncated && s.publicRepo(ctx, base) {
+			s.velocityCache.Set(key, velocity)
+		}
+		return velocity, nil
+	})
+}
+
+// GetRepoVelocity gets a repository's velocity over a window with caching
+func (s *GitHubService) GetRepoVelocity(ctx context.Context, owner, repo string, weeks int, useCache bool) (models.RepoVelocity, error) {
+	if useCache {
+		if velocity, found := s.velocityCache.Get(windowKey(owner, repo, weeks)); found {
+			return velocity, nil
+		}
+	}
+	return s.FetchRepoVelocity(ctx, owner, repo, weeks)
+}
+
+// firstReviews fetches the reviews of the most recent pull requests opened
+// since start, languageWorkers at a time, and maps each pull request that has
+// one to the time of its first review by someone other than its author. It
+// returns the first error alongside the reviews that were fetched.
+func (s *GitHubService) firstReviews(ctx context.Context, base string, items []models.RepoIssue, start time.Time) (map[int]time.Time, error) {
+	type opened struct {
+		number  int
+		author  string
+		created time.Time
+	}
+	var pulls []opened
+	for _, item := range items {
+		created, err := time.Parse(time.RFC3339, item.CreatedAt)
+		if item.PullRequest != nil && err == nil && !created.Before(start) {
+			pulls = append(pulls, opened{item.Number, item.User.Login, created})
+		}
+	}
+	sort.Slice(pulls, func(i, j int) bool { return pulls[i].created.After(pulls[j].created) })
+	if len(pulls) > reviewSampleSize {
+		pulls = pulls[:reviewSampleSize]
+	}
+
+	first := make(map[int]time.Time)
+	var firstErr error
+	var mu sync.Mutex
+	sem := make(chan struct{}, languageWorkers)
+	var wg sync.WaitGroup
+	for _, pull := range pulls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			var reviews []pullReview
+			_, err := s.client.Get(ctx, fmt.Sprintf("%s/pulls/%d/reviews?per_page=%d", base, pull.number, repoSampleSize), &reviews)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			for _, review := range reviews {
+				submitted, err := time.Parse(time.RFC3339, review.SubmittedAt)
+				if err != nil || review.State == "PENDING" || review.User.Login == pull.author || isBot(review.User.Login) {
+					continue
+				}
+				if earliest, ok := first[pull.number]; !ok || submitted.Before(earliest) {
+					first[pull.number] = submitted
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return first, firstErr
+}
+
+// velocitySeries buckets issues and pull requests into weeks from start and
+// summarises the whole window. An item's first response is the earliest of
+// its first comment and, for pull requests, its first review.
+func velocitySeries(items []models.RepoIssue, responses, reviews map[int]time.Time, start time.Time, weeks int) ([]models.VelocityWeek, models.VelocitySummary) {
+	series := make([]models.VelocityWeek, weeks)
+	for i := range series {
+		series[i].WeekStart = start.AddDate(0, 0, 7*i)
+	}
+	samples := make([]struct{ response, close, merge, review []float64 }, weeks)
+	var summary models.VelocitySummary
+	var all struct{ response, close, merge, review []float64 }
+
+	bucket := func(t time.Time) int {
+		if t.Before(start) {
+			return -1
+		}
+		week := int(t.Sub(start).Hours() / (24 * 7))
+		if week >= weeks {
+			return -1
+		}
+		return week
+	}
+
+	for _, item := range items {
+		created, err := time.Parse(time.RFC3339, item.CreatedAt)
+		if err != nil {
+			continue
+		}
+		isPull := item.PullRequest != nil
+
+		if week := bucket(created); week >= 0 {
+			if isPull {
+				series[week].PullsOpened++
+			} else {
+				series[week].IssuesOpened++
+			}
+
+			response, responded := responses[item.Number]
+			if review, reviewed := reviews[item.Number]; reviewed {
+				hours := review.Sub(created).Hours()
+				samples[week].review = append(samples[week].review, hours)
+				all.review = append(all.review, hours)
+				if !responded || review.Before(response) {
+					response, responded = review, true
+				}
+			}
+			if responded {
+				hours := response.Sub(created).Hours()
+				samples[week].response = append(samples[week].response, hours)
+				all.response = append(all.response, hours)
+				summary.Responded++
+			} else {
+				summary.Unanswered++
+			}
+		}
+
+		if isPull && item.PullRequest.MergedAt != "" {
+			merged, err := time.Parse(time.RFC3339, item.PullRequest.MergedAt)
+			if week := bucket(merged); err == nil && week >= 0 {
+				series[week].PullsMerged++
+				hours := merged.Sub(created).Hours()
+				samples[week].merge = append(samples[week].merge, hours)
+				all.merge = append(all.merge, hours)
+			}
+			continue
+		}
+		if item.State != "closed" {
+			continue
+		}
+		closed, err := time.Parse(time.RFC3339, item.ClosedAt)
+		week := bucket(closed)
+		if err != nil || week < 0 {
+			continue
+		}
+		if isPull {
+			series[week].PullsClosed++
+			continue
+		}
+		series[week].IssuesClosed++
+		hours := closed.Sub(created).Hours()
+		samples[week].close = append(samples[week].close, hours)
+		all.close = append(all.close, hours)
+	}
+
+	total := 0
+	for i := range series {
+		series[i].Throughput = series[i].IssuesClosed + series[i].PullsMerged
+		total += series[i].Throughput
+		series[i].MedianFirstResponseHours = medianHours(samples[i].response)
+		series[i].MedianCloseHours = medianHours(samples[i].close)
+		series[i].MedianMergeHours = medianHours(samples[i].merge)
+		series[i].MedianReviewHours = medianHours(samples[i].review)
+	}
+
+	summary.MedianFirstResponseHours = medianHours(all.response)
+	summary.MedianCloseHours = medianHours(all.close)
+	summary.MedianMergeHours = medianHours(all.merge)
+	summary.MedianReviewHours = medianHours(all.review)
+	summary.WeeklyThroughput = math.Round(float64(total)/float64(weeks)*10) / 10
+	return series, summary
+}
+
+// medianHours returns the median of hours rounded to a tenth, or nil without samples
+func medianHours(hours []float64) *float64 {
+	if len(hours) == 0 {
+		return nil
+	}
+	m := math.Round(median(hours)*10) / 10
+	return &m
+}
+
+// weekStart returns midnight UTC on the Monday of t's week
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
@@ -0,0 +1,103 @@
+// Package service provides tests for issue and pull request velocity metrics
+package service
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRepoVelocity(t *testing.T) {
+	defer func(now func() time.Time) { timeNow = now }(timeNow)
+	timeNow = func() time.Time { return time.Date(2024, 7, 3, 12, 0, 0, 0, time.UTC) }
+
+	svc, _ := newFixture(t, map[string]any{
+		"/repos/octocat/hello": `{"full_name":"octocat/hello","private":false}`,
+		"/repos/octocat/hello/issues": func(w http.ResponseWriter, r *http.Request) {
+			if since := r.URL.Query().Get("since"); since != "2024-06-24T00:00:00Z" {
+				t.Errorf("Expected items since the first Monday of the window, got %q", since)
+			}
+			w.Write([]byte(`[
+				{"number":1,"state":"closed","user":{"login":"alice"},"created_at":"2024-06-24T10:00:00Z","closed_at":"2024-06-26T10:00:00Z"},
+				{"number":2,"state":"open","user":{"login":"carol"},"created_at":"2024-07-01T00:00:00Z"},
+				{"number":3,"state":"closed","user":{"login":"dave"},"created_at":"2024-06-25T00:00:00Z","closed_at":"2024-07-02T00:00:00Z","pull_request":{"merged_at":"2024-07-02T00:00:00Z"}},
+				{"number":4,"state":"closed","user":{"login":"dave"},"created_at":"2024-05-01T00:00:00Z","closed_at":"2024-07-02T12:00:00Z","pull_request":{"merged_at":null}}
+			]`))
+		},
+		"/repos/octocat/hello/issues/comments": `[
+			{"issue_url":"https://api.github.com/repos/octocat/hello/issues/1","user":{"login":"alice"},"created_at":"2024-06-24T10:10:00Z"},
+			{"issue_url":"https://api.github.com/repos/octocat/hello/issues/1","user":{"login":"dependabot[bot]"},"created_at":"2024-06-24T10:30:00Z"},
+			{"issue_url":"https://api.github.com/repos/octocat/hello/issues/1","user":{"login":"bob"},"created_at":"2024-06-24T12:00:00Z"},
+			{"issue_url":"https://api.github.com/repos/octocat/hello/issues/3","user":{"login":"frank"},"created_at":"2024-06-25T08:00:00Z"}
+		]`,
+		"/repos/octocat/hello/pulls/3/reviews": `[{"state":"APPROVED","user":{"login":"erin"},"submitted_at":"2024-06-25T06:00:00Z"}]`,
+		"/search/issues": func(w http.ResponseWriter, r *http.Request) {
+			if q := r.URL.Query().Get("q"); q != "repo:octocat/hello is:issue is:open updated:<2024-06-03" {
+				t.Errorf("Unexpected stale issue query %q", q)
+			}
+			w.Write([]byte(`{"total_count":7,"items":[]}`))
+		},
+	})
+
+	ctx := sessionContext()
+	velocity, err := svc.GetRepoVelocity(ctx, "octocat", "hello", 2, true)
+	if err != nil {
+		t.Fatalf("GetRepoVelocity failed: %v", err)
+	}
+	if velocity.Partial || len(velocity.Series) != 2 {
+		t.Fatalf("Expected a complete two-week series, got %+v", velocity)
+	}
+
+	hours := func(h *float64) float64 {
+		if h == nil {
+			return -1
+		}
+		return *h
+	}
+	first, second := velocity.Series[0], velocity.Series[1]
+	if first.IssuesOpened != 1 || first.PullsOpened != 1 || first.IssuesClosed != 1 || first.Throughput != 1 {
+		t.Errorf("Unexpected first week %+v", first)
+	}
+	if hours(first.MedianFirstResponseHours) != 4 || hours(first.MedianCloseHours) != 48 || hours(first.MedianReviewHours) != 6 {
+		t.Errorf("Expected first week response 4h, close 48h and review 6h, got %v, %v and %v",
+			hours(first.MedianFirstResponseHours), hours(first.MedianCloseHours), hours(first.MedianReviewHours))
+	}
+	if second.IssuesOpened != 1 || second.PullsMerged != 1 || second.PullsClosed != 1 || hours(second.MedianMergeHours) != 168 || second.MedianCloseHours != nil {
+		t.Errorf("Unexpected second week %+v", second)
+	}
+
+	summary := velocity.Summary
+	if summary.Responded != 2 || summary.Unanswered != 1 || summary.Reviewed != 1 || summary.StaleIssues != 7 || summary.WeeklyThroughput != 1 {
+		t.Errorf("Unexpected summary %+v", summary)
+	}
+	if _, found := svc.velocityCache.Get("octocat/hello@2"); !found {
+		t.Error("Complete velocity should be cached per window")
+	}
+}
+
+func TestTruncatedVelocityNotCached(t *testing.T) {
+	defer func(now func() time.Time) { timeNow = now }(timeNow)
+	timeNow = func() time.Time { return time.Date(2024, 7, 3, 12, 0, 0, 0, time.UTC) }
+
+	svc, _ := newFixture(t, map[string]any{
+		// The listing never ends, so only the cap stops it
+		"/repos/octocat/hello/issues": func(w http.ResponseWriter, r *http.Request) {
+			nextPage(w, r)
+			w.Write([]byte(`[{"number":1,"state":"open","user":{"login":"alice"},"created_at":"2024-07-01T00:00:00Z"}]`))
+		},
+		"/repos/octocat/hello/issues/comments": `[]`,
+		"/search/issues":                       `{"total_count":0,"items":[]}`,
+	})
+	svc.config.MaxPages = 2
+
+	velocity, err := svc.GetRepoVelocity(sessionContext(), "octocat", "hello", 2, true)
+	if err != nil {
+		t.Fatalf("GetRepoVelocity failed: %v", err)
+	}
+	if !velocity.Truncated || velocity.Partial {
+		t.Errorf("Expected a complete but truncated result, got %+v", velocity)
+	}
+	if _, found := svc.velocityCache.Get("octocat/hello@2"); found {
+		t.Error("Truncated velocity must not be cached")
+	}
+}
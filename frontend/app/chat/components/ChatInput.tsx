@@ -14,8 +14,8 @@ interface ChatInputProps {
     // Mention Popup Props
     showMentionPopup: boolean;
     setShowMentionPopup: (show: boolean) => void;
-    mentionType: "user" | "repo" | "file" | "pr";
-    setMentionType: (type: "user" | "repo" | "file" | "pr") => void;
+    mentionType: "user" | "repo" | "file" | "pr" | "maintainers";
+    setMentionType: (type: "user" | "repo" | "file" | "pr" | "maintainers") => void;
     mentionSearch: string;
     setMentionSearch: (search: string) => void;
     mentionResults: (RepoResult | UserResult)[];
@@ -54,7 +54,7 @@ export const ChatInput: React.FC<ChatInputProps> = ({
                     <div className="flex flex-wrap gap-2 mb-3">
                         {currentMentions.map((m, i) => (
                             <span key={i} className="inline-flex items-center gap-1.5 px-3 py-1.5 bg-[#FF6D1F]/20 border border-[#FF6D1F]/30 rounded-lg text-xs font-medium text-[#FF8A47]">
-                                {m.type === "repo" ? "📁" : m.type === "file" ? "📄" : m.type === "pr" ? "📋" : "👤"} {m.value}
+                                {m.type === "repo" ? "📁" : m.type === "file" ? "📄" : m.type === "pr" ? "📋" : m.type === "maintainers" ? "🛡️" : "👤"} {m.value}
                                 <button onClick={() => setCurrentMentions(prev => prev.filter((_, idx) => idx !== i))} className="ml-1 text-[#FF6D1F] hover:text-[#F5E7C6]">×</button>
                             </span>
                         ))}
@@ -93,6 +93,12 @@ export const ChatInput: React.FC<ChatInputProps> = ({
                                         >
                                             📋 PR
                                         </button>
+                                        <button
+                                            onClick={() => setMentionType("maintainers")}
+                                            className={`px-2 py-1 lg:px-3 lg:py-1.5 rounded-lg text-[10px] lg:text-xs font-medium transition-all ${mentionType === "maintainers" ? "bg-[#FF6D1F] text-white" : "bg-[#1E2345] text-[#A8A0B8] hover:text-[#F5E7C6]"}`}
+                                        >
+                                            🛡️ Maintainers
+                                        </button>
                                     </div>
                                     {mentionType !== "file" && mentionType !== "pr" && (
                                         <input
@@ -100,7 +106,7 @@ export const ChatInput: React.FC<ChatInputProps> = ({
                                             type="text"
                                             value={mentionSearch}
                                             onChange={(e) => setMentionSearch(e.target.value)}
-                                            placeholder={`Search ${mentionType === "repo" ? "repos" : "users"}...`}
+                                            placeholder={`Search ${mentionType === "repo" || mentionType === "maintainers" ? "repos" : "users"}...`}
                                             className="w-full lg:flex-1 px-3 py-2 bg-[#1E2345] border border-[#F5E7C6]/10 rounded-lg text-[#F5E7C6] text-sm placeholder:text-[#6B6580] focus:outline-none focus:border-[#FF6D1F]/50 focus:ring-2 focus:ring-[#FF6D1F]/20"
                                             autoFocus
                                         />
@@ -33,7 +33,7 @@ export default function ChatPage() {
     const [mentionResults, setMentionResults] = useState<(RepoResult | UserResult)[]>([]);
     const [searchLoading, setSearchLoading] = useState(false);
     const [currentMentions, setCurrentMentions] = useState<DevAIMention[]>([]);
-    const [mentionType, setMentionType] = useState<"user" | "repo" | "file" | "pr">("user");
+    const [mentionType, setMentionType] = useState<"user" | "repo" | "file" | "pr" | "maintainers">("user");
 
     // Delete confirmation state
     const [deleteConfirmId, setDeleteConfirmId] = useState<number | null>(null);
@@ -186,7 +186,7 @@ export default function ChatPage() {
     };
 
     // Search for users/repos with GitHub API via our backend
-    const searchMentions = useCallback(async (query: string, type: "repo" | "user" | "file" | "pr") => {
+    const searchMentions = useCallback(async (query: string, type: "repo" | "user" | "file" | "pr" | "maintainers") => {
         if (type === "file" || type === "pr") return; // File and PR paths don't need search
         if (!query.trim()) {
             setMentionResults([]);
@@ -195,7 +195,7 @@ export default function ChatPage() {
 
         setSearchLoading(true);
         try {
-            const endpoint = type === "repo" ? "repos" : "users";
+            const endpoint = type === "repo" || type === "maintainers" ? "repos" : "users";
             const response = await fetch(`${API_BASE}/api/devai/search/${endpoint}?q=${encodeURIComponent(query)}`, {
                 credentials: "include"
             });
@@ -267,7 +267,7 @@ export default function ChatPage() {
     const addMention = (result: RepoResult | UserResult) => {
         const isRepo = "full_name" in result;
         const mention: DevAIMention = {
-            type: isRepo ? "repo" : "user",
+            type: isRepo ? (mentionType === "maintainers" ? "maintainers" : "repo") : "user",
             value: isRepo ? (result as RepoResult).full_name : (result as UserResult).login
         };
 
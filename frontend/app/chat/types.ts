@@ -1,5 +1,5 @@
 export interface DevAIMention {
-    type: "repo" | "user" | "file" | "pr";
+    type: "repo" | "user" | "file" | "pr" | "maintainers";
     value: string;
 }
 
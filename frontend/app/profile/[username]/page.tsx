@@ -7,6 +7,7 @@ import { useAuth } from "@/contexts/AuthContext";
 import { Footer } from "@/components/Footer";
 import { Navbar } from "@/components/Navbar";
 import { RepoCard } from "@/components/RepoCard";
+import { ContributionHeatmap } from "@/components/ContributionHeatmap";
 import { Repository, GitHubFollower } from "@/types/github";
 import Image from "next/image";
 import Link from "next/link";
@@ -825,6 +826,14 @@ export default function ProfilePage() {
                                                 <p className="text-xs text-[#6B6580]">Total Active Days</p>
                                             </div>
                                         </div>
+                                        {streak.calendar && streak.calendar.length > 0 && (
+                                            <div className="mt-4">
+                                                <p className="text-xs text-[#6B6580] mb-2">
+                                                    {streak.total_contributions} contributions in the last year
+                                                </p>
+                                                <ContributionHeatmap days={streak.calendar} />
+                                            </div>
+                                        )}
                                     </div>
                                 )}
                             </div>
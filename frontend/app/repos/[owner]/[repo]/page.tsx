@@ -7,63 +7,17 @@ import { Footer } from "@/components/Footer";
 import { AIAnalysisResult } from "@/components/AIAnalysisResult";
 import { AIAnalysisButton } from "@/components/AIAnalysisButton";
 import { useAIAnalysis } from "@/hooks/useAIAnalysis";
+import { api } from "@/lib/api";
+import type { RepoDetails, RepoCommit, RepoIssue, RepoContributor, RepoMetrics, RepoHealth } from "@/types";
 import Image from "next/image";
 import Link from "next/link";
 
-interface Repository {
-    id: number;
-    name: string;
-    full_name: string;
-    description: string | null;
-    html_url: string;
-    clone_url: string;
-    language: string | null;
-    stargazers_count: number;
-    forks_count: number;
-    watchers_count: number;
-    open_issues_count: number;
-    private: boolean;
-    updated_at: string;
-    created_at: string;
-    topics?: string[];
-    size: number;
-    default_branch: string;
-    license?: { name: string } | null;
-    owner: { login: string; avatar_url: string; html_url: string };
-}
-
-interface Commit {
-    sha: string;
-    commit: { message: string; author: { name: string; date: string } };
-    author: { login: string; avatar_url: string } | null;
-    html_url: string;
-}
-
-interface Issue {
-    id: number;
-    number: number;
-    title: string;
-    state: string;
-    html_url: string;
-    created_at: string;
-    labels: Array<{ name: string; color: string }>;
-    user: { login: string; avatar_url: string };
-}
-
 interface Language {
     name: string;
     percentage: number;
     color: string;
 }
 
-interface Contributor {
-    id: number;
-    login: string;
-    avatar_url: string;
-    html_url: string;
-    contributions: number;
-}
-
 const LANGUAGE_COLORS: Record<string, string> = {
     TypeScript: "#3178c6", JavaScript: "#f1e05a", Python: "#3572A5", Go: "#00ADD8",
     Rust: "#dea584", Java: "#b07219", "C++": "#f34b7d", C: "#555555", Ruby: "#701516",
@@ -76,15 +30,17 @@ export default function RepoDetailPage() {
     const owner = params.owner as string;
     const repo = params.repo as string;
 
-    const [repoData, setRepoData] = useState<Repository | null>(null);
-    const [commits, setCommits] = useState<Commit[]>([]);
-    const [issues, setIssues] = useState<Issue[]>([]);
+    const [repoData, setRepoData] = useState<RepoDetails | null>(null);
+    const [commits, setCommits] = useState<RepoCommit[]>([]);
+    const [issues, setIssues] = useState<RepoIssue[]>([]);
     const [languages, setLanguages] = useState<Language[]>([]);
+    const [metrics, setMetrics] = useState<RepoMetrics | null>(null);
+    const [health, setHealth] = useState<RepoHealth | null>(null);
     const [loading, setLoading] = useState(true);
     const [error, setError] = useState<string | null>(null);
     const [copiedClone, setCopiedClone] = useState(false);
     const [activeTab, setActiveTab] = useState<"commits" | "issues" | "contributors">("commits");
-    const [contributors, setContributors] = useState<Contributor[]>([]);
+    const [contributors, setContributors] = useState<RepoContributor[]>([]);
     const [page, setPage] = useState(1);
 
     // AI Analysis hook with built-in rate limiting
@@ -94,29 +50,18 @@ export default function RepoDetailPage() {
         setLoading(true);
         setError(null);
         try {
-            const [repoRes, commitsRes, issuesRes, langsRes, contributorsRes] = await Promise.all([
-                fetch(`https://api.github.com/repos/${owner}/${repo}`, { headers: { Accept: "application/vnd.github.v3+json" } }),
-                fetch(`https://api.github.com/repos/${owner}/${repo}/commits?per_page=30`, { headers: { Accept: "application/vnd.github.v3+json" } }),
-                fetch(`https://api.github.com/repos/${owner}/${repo}/issues?state=all&per_page=30`, { headers: { Accept: "application/vnd.github.v3+json" } }),
-                fetch(`https://api.github.com/repos/${owner}/${repo}/languages`, { headers: { Accept: "application/vnd.github.v3+json" } }),
-                fetch(`https://api.github.com/repos/${owner}/${repo}/contributors?per_page=30`, { headers: { Accept: "application/vnd.github.v3+json" } }),
-            ]);
-
-            if (!repoRes.ok) throw new Error("Repository not found");
-            setRepoData(await repoRes.json());
-            if (commitsRes.ok) setCommits(await commitsRes.json());
-            if (issuesRes.ok) setIssues(await issuesRes.json());
-
-            if (langsRes.ok) {
-                const langsJson = await langsRes.json();
-                const total = Object.values(langsJson).reduce((a: number, b) => a + (b as number), 0);
-                setLanguages(Object.entries(langsJson).map(([name, bytes]) => ({
-                    name,
-                    percentage: Math.round(((bytes as number) / total) * 1000) / 10,
-                    color: LANGUAGE_COLORS[name] || "#6B6580",
-                })).sort((a, b) => b.percentage - a.percentage));
-            }
-            if (contributorsRes.ok) setContributors(await contributorsRes.json());
+            // One cached, server-side request instead of five anonymous GitHub calls
+            const analytics = await api.getRepoAnalytics(owner, repo);
+            setRepoData(analytics.repo);
+            setCommits(analytics.commits ?? []);
+            setIssues(analytics.issues ?? []);
+            setLanguages((analytics.languages ?? []).map((l) => ({
+                name: l.language,
+                percentage: l.percent,
+                color: LANGUAGE_COLORS[l.language] || "#6B6580",
+            })));
+            setContributors(analytics.contributors ?? []);
+            setMetrics(analytics.metrics);
         } catch (err) {
             setError(err instanceof Error ? err.message : "Failed to load repository");
         } finally {
@@ -128,6 +73,11 @@ export default function RepoDetailPage() {
         if (owner && repo) fetchRepoData();
     }, [owner, repo, fetchRepoData]);
 
+    // The health checklist is optional and loads after the page
+    useEffect(() => {
+        if (owner && repo) api.getRepoHealth(owner, repo).then(setHealth);
+    }, [owner, repo]);
+
     const copyCloneCommand = () => {
         if (repoData) {
             navigator.clipboard.writeText(`git clone ${repoData.clone_url}`);
@@ -303,6 +253,45 @@ export default function RepoDetailPage() {
                                 </div>
                             )}
 
+                            {/* Activity */}
+                            {metrics && (
+                                <div className="premium-card p-4">
+                                    <h3 className="text-sm font-semibold text-[#F5E7C6] mb-3 font-['Gotham']">Activity</h3>
+                                    <div className="grid grid-cols-3 gap-2">
+                                        {[
+                                            { value: metrics.commit_cadence.commits_per_week, label: "Commits / week" },
+                                            { value: `${Math.round(metrics.issues.open_ratio * 100)}%`, label: "Issues open" },
+                                            { value: `${metrics.contributors.top_share}%`, label: "Top contributor" },
+                                        ].map((stat) => (
+                                            <div key={stat.label} className="text-center p-2 bg-[#1E2345]/60 rounded-lg">
+                                                <p className="text-lg font-bold text-[#F5E7C6]">{stat.value}</p>
+                                                <p className="text-[10px] text-[#6B6580]">{stat.label}</p>
+                                            </div>
+                                        ))}
+                                    </div>
+                                </div>
+                            )}
+
+                            {/* Health */}
+                            {health && (
+                                <div className="premium-card p-4">
+                                    <div className="flex items-center justify-between mb-3">
+                                        <h3 className="text-sm font-semibold text-[#F5E7C6] font-['Gotham']">Health</h3>
+                                        <span className="text-sm font-bold text-[#FF6D1F]">{health.score}/{health.max_score}</span>
+                                    </div>
+                                    <div className="space-y-1.5">
+                                        {health.checks.map((check) => (
+                                            <div key={check.id} className="flex items-center justify-between text-xs" title={check.detail}>
+                                                <span className={check.passed ? "text-[#D4C9A8]" : "text-[#6B6580]"}>
+                                                    {check.unscored ? "–" : check.passed ? "✓" : check.score > 0 ? "◐" : "✗"} {check.label}
+                                                </span>
+                                                <span className="text-[#6B6580]">{check.unscored ? "n/a" : `${check.score}/${check.max_score}`}</span>
+                                            </div>
+                                        ))}
+                                    </div>
+                                </div>
+                            )}
+
                             {/* Action Buttons */}
                             <div className="space-y-2">
                                 <a
@@ -404,7 +393,7 @@ export default function RepoDetailPage() {
                                 {/* Content */}
                                 <div className="p-4 space-y-3 min-h-[500px]">
                                     {activeTab === "commits" && paginatedItems.map((item) => {
-                                        const commit = item as Commit;
+                                        const commit = item as RepoCommit;
                                         return (
                                             <a
                                                 key={commit.sha}
@@ -433,7 +422,7 @@ export default function RepoDetailPage() {
                                     })}
 
                                     {activeTab === "issues" && paginatedItems.map((item) => {
-                                        const issue = item as Issue;
+                                        const issue = item as RepoIssue;
                                         return (
                                             <a
                                                 key={issue.id}
@@ -450,7 +439,7 @@ export default function RepoDetailPage() {
                                                 <div className="flex-1 min-w-0">
                                                     <p className="text-sm text-[#F5E7C6] font-medium group-hover:text-[#FF6D1F] transition-colors line-clamp-1">{issue.title}</p>
                                                     <div className="flex flex-wrap items-center gap-2 mt-1.5">
-                                                        {issue.labels.slice(0, 2).map((l) => (
+                                                        {(issue.labels ?? []).slice(0, 2).map((l) => (
                                                             <span key={l.name} className="px-1.5 py-0.5 text-[9px] rounded-full" style={{ backgroundColor: `#${l.color}25`, color: `#${l.color}` }}>{l.name}</span>
                                                         ))}
                                                         <span className="text-xs text-[#6B6580]">#{issue.number} • {formatTime(issue.created_at)}</span>
@@ -461,7 +450,7 @@ export default function RepoDetailPage() {
                                     })}
 
                                     {activeTab === "contributors" && paginatedItems.map((item) => {
-                                        const contributor = item as Contributor;
+                                        const contributor = item as RepoContributor;
                                         return (
                                             <Link
                                                 key={contributor.id}
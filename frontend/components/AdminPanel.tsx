@@ -3,6 +3,7 @@
 import { useState, useEffect } from "react";
 import { api } from "@/lib/api";
 import { AnalysisAnimation } from "./AnalysisAnimation";
+import type { RateLimitStatus } from "@/types";
 
 interface AdminStatus {
     total_users: number;
@@ -12,6 +13,7 @@ interface AdminStatus {
     last_update: string | null;
     is_admin: boolean;
     admin_username: string;
+    github_tokens?: RateLimitStatus[];
 }
 
 interface UpdateResult {
@@ -140,6 +142,16 @@ export function AdminPanel() {
                             </svg>
                             <span className="text-red-500">Disabled: {status.disabled_users ?? 0}</span>
                         </div>
+                        {status.github_tokens?.map((t) => (
+                            <div key={`${t.token}-${t.resource}`} className="flex items-center justify-between text-xs text-gray-400">
+                                <span className={t.disabled ? "line-through text-red-500" : ""}>
+                                    {t.token} ({t.resource})
+                                </span>
+                                <span>
+                                    {t.remaining}/{t.limit} left · {t.requests} sent
+                                </span>
+                            </div>
+                        ))}
                     </div>
                 )}
 
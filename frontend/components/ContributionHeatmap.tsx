@@ -0,0 +1,37 @@
+"use client";
+
+import type { ContributionDay } from "@/types";
+
+interface ContributionHeatmapProps {
+    days: ContributionDay[];
+}
+
+// Colors for contribution levels 0 (none) to 4 (top quartile)
+const LEVEL_COLORS = ["#1E2345", "#5C2E1A", "#8F3E17", "#C85419", "#FF6D1F"];
+
+export function ContributionHeatmap({ days }: ContributionHeatmapProps) {
+    // GitHub's calendar starts each week on Sunday, so every 7 days form a column
+    const weeks: ContributionDay[][] = [];
+    for (let i = 0; i < days.length; i += 7) {
+        weeks.push(days.slice(i, i + 7));
+    }
+
+    return (
+        <div className="overflow-x-auto">
+            <div className="flex gap-[3px] w-max">
+                {weeks.map((week) => (
+                    <div key={week[0].date} className="flex flex-col gap-[3px]">
+                        {week.map((day) => (
+                            <div
+                                key={day.date}
+                                className="w-[10px] h-[10px] rounded-[2px]"
+                                style={{ backgroundColor: LEVEL_COLORS[day.level] ?? LEVEL_COLORS[0] }}
+                                title={`${day.count} contribution${day.count === 1 ? "" : "s"} on ${day.date}`}
+                            />
+                        ))}
+                    </div>
+                ))}
+            </div>
+        </div>
+    );
+}
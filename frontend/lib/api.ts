@@ -9,6 +9,14 @@ import type {
   AIAnalyzeRequest,
   AIAnalyzeResponse,
   ExtendedUserResponse,
+  RateLimitStatus,
+  RepoAnalytics,
+  RepoHealth,
+  ContributorConcentration,
+  RepoVelocity,
+  UserActivity,
+  UserNetwork,
+  UserComparison,
 } from "@/types";
 
 const API_BASE = process.env.NEXT_PUBLIC_API_URL || "http://localhost:8000";
@@ -166,6 +174,18 @@ export const api = {
     await axiosInstance.post("/api/cache/clear");
   },
 
+  async compareUsers(usernames: string[]): Promise<UserComparison | null> {
+    try {
+      const { data } = await axiosInstance.post<{
+        error: boolean;
+        data: UserComparison;
+      }>("/api/compare", { usernames });
+      return data.error ? null : data.data;
+    } catch {
+      return null;
+    }
+  },
+
   async getAIComparison(users: GitHubUser[]): Promise<AIComparisonResponse> {
     const { data } = await axiosInstance.post<AIComparisonResponse>(
       "/api/ai/compare",
@@ -188,7 +208,10 @@ export const api = {
         error: boolean;
         message?: string;
         data: ExtendedUserResponse;
-      }>(`/api/user/${username}/extended`);
+      }>(`/api/user/${username}/extended`, {
+        // Count streak days in the viewer's own time zone
+        params: { tz: Intl.DateTimeFormat().resolvedOptions().timeZone },
+      });
       if (data.error) {
         return {
           error: true,
@@ -212,6 +235,106 @@ export const api = {
     }
   },
 
+  async getUserActivity(username: string): Promise<UserActivity | null> {
+    try {
+      const { data } = await axiosInstance.get<{
+        error: boolean;
+        data: UserActivity;
+      }>(`/api/user/${username}/activity`, {
+        // Place events in the viewer's own time zone
+        params: { tz: Intl.DateTimeFormat().resolvedOptions().timeZone },
+      });
+      return data.error ? null : data.data;
+    } catch {
+      return null;
+    }
+  },
+
+  async getUserNetwork(username: string): Promise<UserNetwork | null> {
+    try {
+      const { data } = await axiosInstance.get<{
+        error: boolean;
+        data: UserNetwork;
+      }>(`/api/user/${username}/network`);
+      return data.error ? null : data.data;
+    } catch {
+      return null;
+    }
+  },
+
+  // GraphML export of the collaboration graph, for graph tools
+  getUserNetworkGraphMLUrl(username: string): string {
+    return `${API_BASE}/api/user/${encodeURIComponent(username)}/network?format=graphml`;
+  },
+
+  async getRepoAnalytics(owner: string, repo: string): Promise<RepoAnalytics> {
+    try {
+      const { data } = await axiosInstance.get<{
+        error: boolean;
+        message?: string;
+        data: RepoAnalytics;
+      }>(`/api/repos/${owner}/${repo}`);
+      if (data.error) {
+        throw new Error(data.message || "Failed to load repository");
+      }
+      return data.data;
+    } catch (error: unknown) {
+      if (isAxiosError(error)) {
+        if (error.response?.status === 404) {
+          throw new Error("Repository not found");
+        }
+        if (error.response?.data?.message) {
+          throw new Error(error.response.data.message);
+        }
+      }
+      throw error instanceof Error ? error : new Error("Failed to load repository");
+    }
+  },
+
+  async getRepoHealth(owner: string, repo: string): Promise<RepoHealth | null> {
+    try {
+      const { data } = await axiosInstance.get<{
+        error: boolean;
+        data: RepoHealth;
+      }>(`/api/repos/${owner}/${repo}/health`);
+      return data.error ? null : data.data;
+    } catch {
+      return null;
+    }
+  },
+
+  async getContributorConcentration(
+    owner: string,
+    repo: string,
+    days = 90
+  ): Promise<ContributorConcentration | null> {
+    try {
+      const { data } = await axiosInstance.get<{
+        error: boolean;
+        data: ContributorConcentration;
+      }>(`/api/repos/${owner}/${repo}/contributors`, { params: { days } });
+      return data.error ? null : data.data;
+    } catch {
+      return null;
+    }
+  },
+
+  async getRepoVelocity(
+    owner: string,
+    repo: string,
+    weeks = 12
+  ): Promise<RepoVelocity | null> {
+    try {
+      const { data } = await axiosInstance.get<{
+        error: boolean;
+        data: RepoVelocity;
+      }>(`/api/repos/${owner}/${repo}/velocity`, { params: { weeks } });
+      return data.error ? null : data.data;
+    } catch {
+      return null;
+    }
+  },
+
   // Auth endpoints
   async getCurrentUser(): Promise<AuthResponse> {
     try {
@@ -304,6 +427,7 @@ export const api = {
     last_update: string | null;
     is_admin: boolean;
     admin_username: string;
+    github_tokens?: RateLimitStatus[];
   }> {
     const { data } = await axiosInstance.get("/api/admin/update-status");
     return data;
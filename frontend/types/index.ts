@@ -17,10 +17,21 @@ export interface GitHubUser {
   public_gists: number;
 }
 
+export interface LanguageShare {
+  language: string;
+  bytes: number;
+  percent: number;
+}
+
 export interface TechStack {
   languages: Record<string, number>;
   top_language: string;
   total_repos: number;
+  truncated?: boolean;
+  mode: 'count' | 'bytes';
+  breakdown?: LanguageShare[];
+  recent?: LanguageShare[];
+  repos_analyzed?: number;
 }
 
 export interface StreakInfo {
@@ -28,6 +39,16 @@ export interface StreakInfo {
   longest_streak: number;
   total_days: number;
   last_active: string;
+  truncated?: boolean;
+  source?: "calendar" | "events";
+  total_contributions?: number;
+  calendar?: ContributionDay[];
+}
+
+export interface ContributionDay {
+  date: string;
+  count: number;
+  level: number;
 }
 
 export interface ExtendedUserInfo {
@@ -50,6 +71,8 @@ export interface APIResponse {
   message?: string;
   data?: GitHubUser;
   cached?: boolean;
+  stale?: boolean;
+  reset_at?: string;
 }
 
 export interface BatchResponse {
@@ -57,17 +80,325 @@ export interface BatchResponse {
   results: Record<string, APIResponse>;
 }
 
+export interface RateLimitStatus {
+  token: string;
+  resource: string;
+  limit: number;
+  remaining: number;
+  used: number;
+  reset_at: string;
+  blocked_until?: string;
+  requests: number;
+  disabled?: boolean;
+}
+
+export interface RetryStats {
+  upstream: string;
+  retries: number;
+  recovered: number;
+  exhausted: number;
+}
+
+export interface BreakerStatus {
+  upstream: string;
+  state: "closed" | "open" | "half-open";
+  consecutive_failures: number;
+  trips: number;
+  rejected: number;
+  retry_at?: string;
+}
+
 export interface HealthResponse {
   status: string;
   cache_size?: number;
   cache_hit_rate?: string;
+  github_rate_limit?: RateLimitStatus[];
+  retries?: RetryStats[];
+  breakers?: BreakerStatus[];
 }
 
 export interface CacheStats {
   size: number;
+  max_size?: number;
+  ttl_seconds?: number;
   hits: number;
   misses: number;
+  revalidations?: number;
+  not_modified?: number;
+  evictions?: number;
+  expirations?: number;
   hit_rate: string;
+  github_rate_limit?: RateLimitStatus[];
+}
+
+// Repository analytics from /api/repos/{owner}/{repo}
+export interface RepoOwner {
+  login: string;
+  avatar_url: string;
+  html_url: string;
+}
+
+export interface RepoDetails {
+  id: number;
+  name: string;
+  full_name: string;
+  description: string;
+  html_url: string;
+  clone_url: string;
+  homepage: string;
+  language: string;
+  stargazers_count: number;
+  forks_count: number;
+  watchers_count: number;
+  open_issues_count: number;
+  private: boolean;
+  fork: boolean;
+  archived: boolean;
+  topics: string[] | null;
+  size: number;
+  default_branch: string;
+  license: { name: string } | null;
+  owner: RepoOwner;
+  created_at: string;
+  updated_at: string;
+  pushed_at: string;
+}
+
+export interface RepoCommit {
+  sha: string;
+  html_url: string;
+  commit: { message: string; author: { name: string; email: string; date: string } };
+  author: RepoOwner | null;
+}
+
+export interface RepoIssue {
+  id: number;
+  number: number;
+  title: string;
+  state: string;
+  html_url: string;
+  comments: number;
+  labels: Array<{ name: string; color: string }> | null;
+  user: RepoOwner;
+  created_at: string;
+  closed_at: string;
+}
+
+export interface RepoContributor {
+  id: number;
+  login: string;
+  avatar_url: string;
+  html_url: string;
+  contributions: number;
+}
+
+export interface RepoMetrics {
+  commit_cadence: {
+    sampled: number;
+    commits_per_week: number;
+    median_gap_hours: number;
+    last_commit_at?: string;
+  };
+  issues: { open: number; closed: number; open_ratio: number };
+  contributors: {
+    contributors: number;
+    top_contributor?: string;
+    top_share: number;
+    top_five_share: number;
+    total_contributions: number;
+  };
+}
+
+export interface RepoAnalytics {
+  repo: RepoDetails;
+  commits: RepoCommit[] | null;
+  issues: RepoIssue[] | null;
+  languages: LanguageShare[] | null;
+  contributors: RepoContributor[] | null;
+  metrics: RepoMetrics;
+  partial?: boolean;
+}
+
+export interface HealthCheck {
+  id: string;
+  label: string;
+  passed: boolean;
+  unscored?: boolean;
+  score: number;
+  max_score: number;
+  detail: string;
+}
+
+export interface RepoHealth {
+  repo: string;
+  score: number;
+  max_score: number;
+  checks: HealthCheck[];
+  partial?: boolean;
+}
+
+export interface ContributorActivity {
+  login: string;
+  commits: number;
+  share: number;
+}
+
+export interface MaintainerChurn {
+  previous: number;
+  current: number;
+  retained: number;
+  joined: string[];
+  left: string[];
+  churn_rate: number;
+}
+
+export interface ContributorConcentration {
+  repo: string;
+  window_days: number;
+  since: string;
+  commits: number;
+  contributors: ContributorActivity[];
+  bus_factor: number;
+  gini: number;
+  top_share: number;
+  churn: MaintainerChurn | null;
+  all_time?: ContributorActivity[];
+  all_time_bus_factor?: number;
+  stats_pending?: boolean;
+  truncated?: boolean;
+}
+
+export interface ActivityBreakdown {
+  push: number;
+  pull_request: number;
+  review: number;
+  issue: number;
+  release: number;
+  other: number;
+}
+
+export interface ActivityTrendDay {
+  date: string;
+  events: number;
+  rolling_30: number;
+}
+
+export interface ActiveRepo {
+  name: string;
+  events: number;
+  last_active: string;
+}
+
+export interface UserActivity {
+  username: string;
+  time_zone: string;
+  total_events: number;
+  since?: string;
+  heatmap: number[][]; // [weekday, 0 = Sunday][hour]
+  breakdown: ActivityBreakdown;
+  trend: ActivityTrendDay[];
+  trend_change: number;
+  top_repos: ActiveRepo[];
+  truncated?: boolean;
+}
+
+export interface NetworkNode {
+  id: string;
+  type: "user" | "repo";
+  label: string;
+  weight: number;
+}
+
+export interface NetworkEdge {
+  source: string;
+  target: string;
+  kind: "contributor" | "activity" | "review" | "issue_comment";
+  weight: number;
+}
+
+export interface UserNetwork {
+  username: string;
+  nodes: NetworkNode[];
+  edges: NetworkEdge[];
+  partial?: boolean;
+}
+
+export interface Overlap {
+  count: number;
+  items: string[];
+}
+
+export interface ComparedUser {
+  login: string;
+  followers: number;
+  following: number;
+  starred: number;
+  contributed_repos: number;
+  truncated?: boolean;
+}
+
+export interface ComparisonPair {
+  users: [string, string];
+  first_follows_second: boolean;
+  second_follows_first: boolean;
+  mutual_follow: boolean;
+  shared_followers: Overlap;
+  shared_following: Overlap;
+  shared_repos: Overlap;
+  shared_stars: Overlap;
+  star_similarity: number;
+  language_similarity: number;
+}
+
+export interface CommonGround {
+  followers: Overlap;
+  following: Overlap;
+  repos: Overlap;
+  stars: Overlap;
+}
+
+export interface UserComparison {
+  users: ComparedUser[];
+  pairs: ComparisonPair[];
+  common: CommonGround;
+  partial?: boolean;
+}
+
+export interface VelocityWeek {
+  week_start: string;
+  issues_opened: number;
+  issues_closed: number;
+  pulls_opened: number;
+  pulls_merged: number;
+  pulls_closed: number;
+  throughput: number;
+  median_first_response_hours: number | null;
+  median_close_hours: number | null;
+  median_merge_hours: number | null;
+  median_review_hours: number | null;
+}
+
+export interface VelocitySummary {
+  median_first_response_hours: number | null;
+  median_close_hours: number | null;
+  median_merge_hours: number | null;
+  median_review_hours: number | null;
+  responded: number;
+  unanswered: number;
+  reviewed: number;
+  stale_issues: number;
+  stale_days: number;
+  weekly_throughput: number;
+}
+
+export interface RepoVelocity {
+  repo: string;
+  weeks: number;
+  since: string;
+  summary: VelocitySummary;
+  series: VelocityWeek[];
+  truncated?: boolean;
+  partial?: boolean;
 }
 
 export interface AIComparisonResponse {